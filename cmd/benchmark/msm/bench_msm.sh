@@ -4,14 +4,24 @@ set -e
 EXPS=$(seq 10 30)           # exp range (10 ~ 30)
 PROCS=(10)          # number of processes
 ITERS=1                     # number of iterations
-MODES=("const")      # benchmark modes: "const", "rand"
+MODES=("const")      # benchmark modes: "const", "rand", "binary", "ternary", "small", "sparse"
+IMPLS=("multiexp")   # "multiexp", "naive", "pippenger", "signed", "batchaffine", "fixedbase", "class"
+WINDOW=0             # Pippenger window bits (0: chosen from n)
+CURVES=("bn254")     # "bn254", "bls12-381", "bls12-377"
+POINT_GROUPS=("g1")  # "g1", "g2" (outside bn254 g1: impl "multiexp"/"naive", mode "const"/"rand")
 
 # Run benchmarks
 for mode in "${MODES[@]}"; do
   for exp in $EXPS; do
     for procs in "${PROCS[@]}"; do
-      echo "Running MSM: mode=$mode, procs=$procs, exp=$exp"
-      go run main.go $exp $ITERS $procs $mode
+      for impl in "${IMPLS[@]}"; do
+        for curve in "${CURVES[@]}"; do
+          for group in "${POINT_GROUPS[@]}"; do
+            echo "Running MSM: curve=$curve, group=$group, mode=$mode, impl=$impl, procs=$procs, exp=$exp"
+            go run main.go $exp $ITERS $procs $mode $impl $WINDOW $curve $group
+          done
+        done
+      done
     done
   done
 done
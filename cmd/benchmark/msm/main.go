@@ -1,14 +1,26 @@
-// go run ./cmd/msmtest <exp> [iters] [maxProcs] [mode]
+// go run ./cmd/msmtest <exp> [iters] [maxProcs] [mode] [impl] [window] [curve] [group]
 //   exp      : n = 2^exp
 //   iters    : number of iterations (default 5)
 //   maxProcs : GOMAXPROCS setting (default -1: number of CPU cores)
-//   mode     : "const" (default) or "rand"
+//   mode     : "const" (default), "rand", or random points with "binary" ({0,1}),
+//              "ternary" ({-1,0,1}), "small" (signed 16-bit) or "sparse" (1/16
+//              non-zero) scalars; the last four are checked against MultiExpMSM
+//   impl     : "multiexp" (default, gnark-crypto MultiExp), "naive", "pippenger" (in-repo,
+//              unsigned digits), "signed" (signed digits) or "batchaffine"
+//              (signed digits, batch-affine buckets) or "fixedbase" (precomputed
+//              tables, built once outside the timed loop) or "class" (SmallMSM,
+//              scalar class detected)
+//   window   : Pippenger window bits (default 0: chosen from n)
+//   curve    : "bn254" (default), "bls12-381" or "bls12-377"
+//   group    : "g1" (default) or "g2"; other than BN254 G1 only impl "multiexp" /
+//              "naive" and mode "const" / "rand" are available
 
 package main
 
 import (
 	"fmt"
 	"math/big"
+	"math/rand"
 	"os"
 	"runtime"
 	"strconv"
@@ -18,13 +30,15 @@ import (
 	"github.com/Han-16/fwhtist/internal/msm"
 	"github.com/Han-16/fwhtist/internal/randutil"
 
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run ./cmd/msmtest <exp> [iters] [maxProcs] [mode]")
+		fmt.Println("Usage: go run ./cmd/msmtest <exp> [iters] [maxProcs] [mode] [impl] [window] [curve] [group]")
 		return
 	}
 	exp, err := strconv.Atoi(os.Args[1])
@@ -57,19 +71,103 @@ func main() {
 	if len(os.Args) >= 5 {
 		mode = strings.ToLower(os.Args[4])
 	}
-	if mode != "const" && mode != "rand" {
-		panic(`mode must be "const" or "rand"`)
+	switch mode {
+	case "const", "rand", "binary", "ternary", "small", "sparse":
+	default:
+		panic(`mode must be "const", "rand", "binary", "ternary", "small" or "sparse"`)
 	}
 
+	impl := "multiexp"
+	if len(os.Args) >= 6 {
+		impl = strings.ToLower(os.Args[5])
+	}
+	window := 0
+	if len(os.Args) >= 7 {
+		window, err = strconv.Atoi(os.Args[6])
+		must(err)
+	}
+	curve, group := "bn254", "g1"
+	if len(os.Args) >= 8 {
+		curve = strings.ToLower(os.Args[7])
+	}
+	if len(os.Args) >= 9 {
+		group = strings.ToLower(os.Args[8])
+	}
+	if curve != "bn254" && curve != "bls12-381" && curve != "bls12-377" {
+		panic(`curve must be "bn254", "bls12-381" or "bls12-377"`)
+	}
+	if group != "g1" && group != "g2" {
+		panic(`group must be "g1" or "g2"`)
+	}
+	bn254G1 := curve == "bn254" && group == "g1"
+	if !bn254G1 && (impl != "multiexp" && impl != "naive" || mode != "const" && mode != "rand") {
+		panic(`only impl "multiexp" / "naive" and mode "const" / "rand" are available outside BN254 G1`)
+	}
+	var msmFn func([]bn254.G1Affine, []fr.Element) (bn254.G1Affine, error)
+	switch impl {
+	case "multiexp":
+		msmFn = msm.MultiExpMSM
+	case "naive":
+		msmFn = msm.NaiveMSM
+	case "pippenger", "signed", "batchaffine":
+		cfg := msm.PippengerConfig{Window: window, Signed: impl != "pippenger", BatchAffine: impl == "batchaffine", Workers: maxProcs}
+		msmFn = func(p []bn254.G1Affine, s []fr.Element) (bn254.G1Affine, error) {
+			return msm.PippengerMSM(p, s, cfg)
+		}
+	case "fixedbase":
+		// set once the points are known
+	case "class":
+		msmFn = func(p []bn254.G1Affine, s []fr.Element) (bn254.G1Affine, error) {
+			return msm.SmallMSM(p, s, msm.SmallMSMConfig{Workers: maxProcs})
+		}
+	default:
+		panic(`impl must be "multiexp", "naive", "pippenger", "signed", "batchaffine", "fixedbase" or "class"`)
+	}
+
+	// output file: {mode}_procs{maxProcs}.txt ({mode}_{impl}_w{window}_procs{maxProcs}.txt for Pippenger,
+	// {mode}_{curve}_{group}_{impl}_procs{maxProcs}.txt outside BN254 G1)
 	filename := fmt.Sprintf("%s_procs%d.txt", mode, maxProcs)
+	if !bn254G1 {
+		filename = fmt.Sprintf("%s_%s_%s_%s_procs%d.txt", mode, curve, group, impl, maxProcs)
+	} else if impl != "multiexp" {
+		filename = fmt.Sprintf("%s_%s_w%d_procs%d.txt", mode, impl, window, maxProcs)
+	}
 	out, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	must(err)
 	defer out.Close()
 
 	if fi, err := out.Stat(); err == nil && fi.Size() == 0 {
-		fmt.Fprintf(out, "# MSM Benchmark Results (mode=%s, procs=%d)\n", mode, maxProcs)
+		fmt.Fprintf(out, "# MSM Benchmark Results (curve=%s, group=%s, mode=%s, impl=%s, window=%d, procs=%d)\n", curve, group, mode, impl, window, maxProcs)
 		fmt.Fprintln(out, "# exp | n | iters | Best | Avg")
 	}
+	report := func(best, total time.Duration) {
+		avg := time.Duration(int64(total) / int64(iters))
+		fmt.Fprintf(out, "%d | %d | %d | %s | %s\n", exp, n, iters, best, avg)
+		fmt.Printf("Appended: curve=%s, group=%s, mode=%s, impl=%s, procs=%d, exp=%d, iters=%d\n", curve, group, mode, impl, maxProcs, exp, iters)
+	}
+
+	if !bn254G1 {
+		var best, total time.Duration
+		switch curve + "/" + group {
+		case "bn254/g2":
+			_, _, _, g := bn254.Generators()
+			best, total = benchGroup[bn254.G2Affine, bn254.G2Jac](g, bn254.BatchScalarMultiplicationG2, impl, mode, n, iters)
+		case "bls12-381/g1":
+			_, _, g, _ := bls12381.Generators()
+			best, total = benchGroup[bls12381.G1Affine, bls12381.G1Jac](g, bls12381.BatchScalarMultiplicationG1, impl, mode, n, iters)
+		case "bls12-381/g2":
+			_, _, _, g := bls12381.Generators()
+			best, total = benchGroup[bls12381.G2Affine, bls12381.G2Jac](g, bls12381.BatchScalarMultiplicationG2, impl, mode, n, iters)
+		case "bls12-377/g1":
+			_, _, g, _ := bls12377.Generators()
+			best, total = benchGroup[bls12377.G1Affine, bls12377.G1Jac](g, bls12377.BatchScalarMultiplicationG1, impl, mode, n, iters)
+		case "bls12-377/g2":
+			_, _, _, g := bls12377.Generators()
+			best, total = benchGroup[bls12377.G2Affine, bls12377.G2Jac](g, bls12377.BatchScalarMultiplicationG2, impl, mode, n, iters)
+		}
+		report(best, total)
+		return
+	}
 
 	// ---- prepare scalars & points ----
 	var scalars []fr.Element
@@ -118,18 +216,57 @@ func main() {
 		must(err)
 
 		expected = bn254.G1Jac{}
+
+	default:
+		// binary | ternary | small | sparse scalars over random points
+		scalars = make([]fr.Element, n)
+		for i := 0; i < n; i++ {
+			switch mode {
+			case "binary":
+				scalars[i].SetUint64(uint64(rand.Intn(2)))
+			case "ternary":
+				scalars[i].SetInt64(int64(rand.Intn(3) - 1))
+			case "small":
+				scalars[i].SetInt64(int64(rand.Intn(1<<16)) - 1<<15)
+			case "sparse":
+				if rand.Intn(16) == 0 {
+					scalars[i].SetRandom()
+				}
+			}
+		}
+
+		var err error
+		points, err = randutil.RandomPointsG1Par(n, maxProcs)
+		must(err)
+
+		want, err := msm.MultiExpMSM(points, scalars)
+		must(err)
+		expected.FromAffine(&want)
+	}
+	if impl == "class" {
+		fmt.Printf("Scalars: %+v\n", msm.ClassifyScalars(scalars, maxProcs))
+	}
+
+	if impl == "fixedbase" {
+		start := time.Now()
+		fb, err := msm.NewFixedBaseMSM(points, msm.FixedBaseConfig{Window: window, Workers: maxProcs})
+		must(err)
+		fmt.Printf("Precompute: c=%d, stride=%d, %d MiB, %s\n", fb.Window(), fb.Stride(), fb.TableBytes()>>20, time.Since(start))
+		msmFn = func(_ []bn254.G1Affine, s []fr.Element) (bn254.G1Affine, error) {
+			return fb.MSM(s, maxProcs)
+		}
 	}
 
 	// ---- benchmark ----
 	var best, total time.Duration
 	for it := 0; it < iters; it++ {
 		start := time.Now()
-		resAff, err := msm.MultiExpMSM(points, scalars)
+		resAff, err := msmFn(points, scalars)
 		must(err)
 		elapsed := time.Since(start)
 
-		if mode == "const" && !equalAffineJac(resAff, expected) {
-			panic(fmt.Sprintf("iter %d: MSM result mismatch with (n*s)*g", it))
+		if mode != "rand" && !equalAffineJac(resAff, expected) {
+			panic(fmt.Sprintf("iter %d: MSM result mismatch with the expected value", it))
 		}
 		runtime.KeepAlive(resAff)
 
@@ -138,11 +275,74 @@ func main() {
 		}
 		total += elapsed
 	}
-	avg := time.Duration(int64(total) / int64(iters))
 
 	// ---- summary ----
-	fmt.Fprintf(out, "%d | %d | %d | %s | %s\n", exp, n, iters, best, avg)
-	fmt.Printf("Appended: mode=%s, procs=%d, exp=%d, iters=%d\n", mode, maxProcs, exp, iters)
+	report(best, total)
+}
+
+// scalarField is the part of a gnark-crypto fr.Element benchGroup needs.
+type scalarField[S any] interface {
+	msm.ScalarMSM[S]
+	SetRandom() (*S, error)
+	SetUint64(uint64) *S
+	Mul(*S, *S) *S
+}
+
+// benchGroup times impl ("multiexp" | "naive") over one curve and group. Points
+// are [gen, ..., gen] (const, checked against (n*s)*gen) or gen*r_i (rand), both
+// from batchMul (gnark-crypto BatchScalarMultiplicationG1/G2).
+func benchGroup[A comparable, J, S any, PA msm.AffMSM[A, J], PJ msm.JacMSM[J, A, S], PS scalarField[S]](
+	gen A, batchMul func(*A, []S) []A, impl, mode string, n, iters int) (best, total time.Duration) {
+	msmFn := msm.GenericMultiExpMSM[A, J, S, PA, PJ, PS]
+	if impl == "naive" {
+		msmFn = msm.GenericNaiveMSM[A, J, S, PA, PJ, PS]
+	}
+
+	scalars := make([]S, n)
+	var points []A
+	var expected A
+	switch mode {
+	case "const":
+		var s S
+		_, err := PS(&s).SetRandom()
+		must(err)
+		points = make([]A, n)
+		for i := 0; i < n; i++ {
+			scalars[i], points[i] = s, gen
+		}
+
+		var ns S
+		PS(&ns).SetUint64(uint64(n))
+		PS(&ns).Mul(&ns, &s)
+		expected = batchMul(&gen, []S{ns})[0]
+
+	case "rand":
+		r := make([]S, n)
+		for i := 0; i < n; i++ {
+			_, err := PS(&scalars[i]).SetRandom()
+			must(err)
+			_, err = PS(&r[i]).SetRandom()
+			must(err)
+		}
+		points = batchMul(&gen, r)
+	}
+
+	for it := 0; it < iters; it++ {
+		start := time.Now()
+		res, err := msmFn(points, scalars)
+		must(err)
+		elapsed := time.Since(start)
+
+		if mode == "const" && res != expected {
+			panic(fmt.Sprintf("iter %d: MSM result mismatch with (n*s)*g", it))
+		}
+
+		if it == 0 || elapsed < best {
+			best = elapsed
+		}
+		total += elapsed
+	}
+	return best, total
 }
 
 func equalAffineJac(a bn254.G1Affine, b bn254.G1Jac) bool {
@@ -6,13 +6,16 @@ EXPS=$(seq 10 18)           # exp range (10 ~ 30)
 PROCS=(1)          # number of processes
 ITERS=1                     # number of iterations
 MODES=("rand")      # benchmark modes
+VARIANTS=("jac" "mixed" "affine" "engine" "2d")  # coordinate variants (one result file each)
 
 # Run benchmarks
 for mode in "${MODES[@]}"; do
   for exp in $EXPS; do
     for procs in "${PROCS[@]}"; do
-      echo "Running FWHT: mode=$mode, procs=$procs, exp=$exp"
-      go run main.go $exp $ITERS $procs $mode
+      for variant in "${VARIANTS[@]}"; do
+        echo "Running FWHT: mode=$mode, variant=$variant, procs=$procs, exp=$exp"
+        go run main.go $exp $ITERS $procs $mode $variant
+      done
     done
   done
 done
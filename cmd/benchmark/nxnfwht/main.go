@@ -1,4 +1,10 @@
-// go run ./cmd/fwhtbench <exp> [iters] [maxProcs] [mode]
+// go run ./cmd/fwhtbench <exp> [iters] [maxProcs] [mode] [variant]
+//
+//	variant : "jac" (default, MatVecHadamardPar), "mixed" (affine first stage)
+//	          "affine" (all stages affine with batch inversion)
+//	          "engine" (fwht.Engine, pool kept alive across iterations; first
+//	          checks one Engine shared by engineCallers goroutines and after Close)
+//	          or "2d" (H·M·Hᵀ on a 2^ceil(exp/2) × 2^floor(exp/2) matrix)
 package main
 
 import (
@@ -7,17 +13,19 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Han-16/fwhtist/internal/fwht"
 	"github.com/Han-16/fwhtist/internal/randutil"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run ./cmd/fwhtbench <exp> [iters] [maxProcs] [mode]")
+		fmt.Println("Usage: go run ./cmd/fwhtbench <exp> [iters] [maxProcs] [mode] [variant]")
 		return
 	}
 	exp, err := strconv.Atoi(os.Args[1])
@@ -54,6 +62,35 @@ func main() {
 		panic(`mode must be "const" or "rand"`)
 	}
 
+	variant := "jac"
+	if len(os.Args) >= 6 {
+		variant = strings.ToLower(os.Args[5])
+	}
+	var transform func([]bn254.G1Affine, int) ([]bn254.G1Affine, error)
+	switch variant {
+	case "jac":
+		transform = fwht.MatVecHadamardPar
+	case "mixed":
+		transform = fwht.MatVecHadamardParMixed
+	case "affine":
+		transform = func(p []bn254.G1Affine, w int) ([]bn254.G1Affine, error) {
+			return fwht.MatVecHadamardParAffine(p, w, -1)
+		}
+	case "engine":
+		engine := fwht.NewEngine(maxProcs, 0)
+		defer engine.Close()
+		transform = func(p []bn254.G1Affine, _ int) ([]bn254.G1Affine, error) {
+			return engine.MatVecHadamard(p)
+		}
+	case "2d":
+		rows, cols := 1<<(exp-exp/2), 1<<(exp/2)
+		transform = func(p []bn254.G1Affine, w int) ([]bn254.G1Affine, error) {
+			return fwht.MatVecHadamard2DPar(p, rows, cols, fwht.Axes2DBoth, w)
+		}
+	default:
+		panic(`variant must be "jac", "mixed", "affine", "engine" or "2d"`)
+	}
+
 	// prepare points
 	var points []bn254.G1Affine
 	switch mode {
@@ -73,21 +110,28 @@ func main() {
 		must(err)
 	}
 
-	// output file: {mode}_procs_{maxProcs}.txt
+	if variant == "engine" {
+		checkEngine(points, maxProcs)
+	}
+
+	// output file: {mode}_procs_{maxProcs}.txt ({mode}_{variant}_procs_{maxProcs}.txt for non-jac)
 	filename := fmt.Sprintf("%s_procs_%d.txt", mode, maxProcs)
+	if variant != "jac" {
+		filename = fmt.Sprintf("%s_%s_procs_%d.txt", mode, variant, maxProcs)
+	}
 	out, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	must(err)
 	defer out.Close()
 
 	if fi, err := out.Stat(); err == nil && fi.Size() == 0 {
-		fmt.Fprintf(out, "# FWHT Benchmark Results (mode=%s, procs=%d)\n", mode, maxProcs)
+		fmt.Fprintf(out, "# FWHT Benchmark Results (mode=%s, variant=%s, procs=%d)\n", mode, variant, maxProcs)
 		fmt.Fprintln(out, "# exp | n | iters | Best | Avg")
 	}
 
 	var best, total time.Duration
 	for it := 0; it < iters; it++ {
 		start := time.Now()
-		_, err := fwht.MatVecHadamardPar(points, maxProcs)
+		_, err := transform(points, maxProcs)
 		must(err)
 		elapsed := time.Since(start)
 
@@ -99,7 +143,84 @@ func main() {
 	avg := time.Duration(int64(total) / int64(iters))
 
 	fmt.Fprintf(out, "%d | %d | %d | %s | %s\n", exp, n, iters, best, avg)
-	fmt.Printf("FWHT appended: mode=%s, procs=%d, exp=%d, iters=%d\n", mode, maxProcs, exp, iters)
+	fmt.Printf("FWHT appended: mode=%s, variant=%s, procs=%d, exp=%d, iters=%d\n", mode, variant, maxProcs, exp, iters)
+}
+
+// engineCallers is the number of goroutines sharing one Engine in checkEngine.
+const engineCallers = 4
+
+// checkEngine has engineCallers goroutines run every Engine method on one shared
+// Engine and compares each result with the Par function, then repeats one call
+// after Close (and a second Close), and runs the callers again with Close issued
+// while they are running. Run with -race to also check the pool.
+func checkEngine(points []bn254.G1Affine, maxProcs int) {
+	n := len(points)
+	want, err := fwht.MatVecHadamardPar(points, maxProcs)
+	must(err)
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		scalars[i].SetUint64(uint64(i + 1))
+	}
+	wantFr := append([]fr.Element(nil), scalars...)
+	must(fwht.MatVecHadamardFrParInPlace(wantFr, maxProcs))
+
+	// 호출자마다 독립된 입력/scratch 로 세 메서드를 번갈아 실행
+	call := func(engine *fwht.Engine, c int) error {
+		out, err := engine.MatVecHadamard(points)
+		if err != nil {
+			return err
+		}
+		in := append([]bn254.G1Affine(nil), points...)
+		if err := engine.MatVecHadamardScratch(in, make([]bn254.G1Jac, n)); err != nil {
+			return err
+		}
+		v := append([]fr.Element(nil), scalars...)
+		if err := engine.MatVecHadamardFrInPlace(v); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if !out[i].Equal(&want[i]) || !in[i].Equal(&want[i]) || !v[i].Equal(&wantFr[i]) {
+				return fmt.Errorf("caller %d: mismatch at index %d", c, i)
+			}
+		}
+		return nil
+	}
+	// closeEarly: 호출 도중 Close
+	shared := func(closeEarly bool) *fwht.Engine {
+		engine := fwht.NewEngine(maxProcs, 0)
+		errs := make([]error, engineCallers)
+		var wg sync.WaitGroup
+		for c := 0; c < engineCallers; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for it := 0; it < 3 && errs[c] == nil; it++ {
+					errs[c] = call(engine, c)
+				}
+			}()
+		}
+		if closeEarly {
+			engine.Close()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				fmt.Printf("Check failed ❌ : shared Engine (close during calls: %v): %v\n", closeEarly, err)
+				os.Exit(1)
+			}
+		}
+		return engine
+	}
+
+	engine := shared(false)
+	engine.Close()
+	engine.Close()
+	if err := call(engine, 0); err != nil {
+		fmt.Printf("Check failed ❌ : Engine after Close: %v\n", err)
+		os.Exit(1)
+	}
+	shared(true)
+	fmt.Printf("Check passed ✅ : %d goroutines sharing one Engine, calls after and during Close == MatVecHadamardPar\n", engineCallers)
 }
 
 func must(err error) {
@@ -3,6 +3,9 @@
 //   maxProcs: default NumCPU
 //   mode    : const | rand (default const)
 //   iters   : 각 변형을 몇 번 반복할지 (기본 3; best와 avg 출력)
+// Compares per-point vs batch Jac->Aff, radix-2 vs radix-4 vs cache-blocked stages,
+// Jacobian vs mixed-first-stage vs batch-affine coordinates, and the row/column
+// passes of the 2D transform on a 2^ceil(exp/2) × 2^floor(exp/2) matrix.
 package main
 
 import (
@@ -69,6 +72,23 @@ func runPerPoint(points []bn254.G1Affine, procs, iters int) (out []bn254.G1Affin
 	return out, st, nil
 }
 
+// runVariant times a transform without a phase profile (radix-2 / radix-4 / blocked).
+func runVariant(points []bn254.G1Affine, procs, iters int, f func([]bn254.G1Affine, int) ([]bn254.G1Affine, error)) (out []bn254.G1Affine, st runStats, err error) {
+	for it := 0; it < iters; it++ {
+		start := time.Now()
+		out, err = f(points, procs)
+		if err != nil {
+			return nil, st, err
+		}
+		elapsed := time.Since(start)
+		if it == 0 || elapsed < st.best {
+			st.best = elapsed
+		}
+		st.total += elapsed
+	}
+	return out, st, nil
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		fmt.Println("Usage: go run ./nxnfwht_compare <exp> [maxProcs] [mode] [iters]")
@@ -141,4 +161,78 @@ func main() {
 	fmt.Printf("  Total    : %v (100%%)\n",   stBat.lastProf.TTotal)
 
 	fmt.Printf("\nSpeedup (PerPoint / Batch)  Best: %.2fx | Avg: %.2fx\n\n", speedupBest, speedupAvg)
+
+	// 스테이지 융합: radix-2 (현재 구현) vs radix-4 vs cache-blocked
+	blocked := func(p []bn254.G1Affine, w int) ([]bn254.G1Affine, error) {
+		return fwht.MatVecHadamardParBlocked(p, w, 0)
+	}
+	outR2, stR2, err := runVariant(points, maxProcs, iters, fwht.MatVecHadamardPar)
+	must(err)
+	outR4, stR4, err := runVariant(points, maxProcs, iters, fwht.MatVecHadamardParRadix4)
+	must(err)
+	outBlk, stBlk, err := runVariant(points, maxProcs, iters, blocked)
+	must(err)
+
+	fmt.Printf("-- Stage fusion (tile=%d KiB) --\n", fwht.DefaultTileBytes>>10)
+	fmt.Printf("Correctness (radix-4, blocked vs radix-2) : %v, %v\n", eqSlices(outR2, outR4), eqSlices(outR2, outBlk))
+	for _, v := range []struct {
+		name string
+		st   runStats
+	}{{"Radix-2", stR2}, {"Radix-4", stR4}, {"Blocked", stBlk}} {
+		avg := time.Duration(int64(v.st.total) / int64(iters))
+		fmt.Printf("%-8s Best: %v | Avg: %v | Speedup vs Radix-2 (best): %.2fx\n",
+			v.name, v.st.best, avg, float64(stR2.best)/float64(v.st.best))
+	}
+	fmt.Println()
+
+	// 좌표계: Jacobian vs mixed 첫 스테이지 vs 전체 affine (batch inversion)
+	affine := func(p []bn254.G1Affine, w int) ([]bn254.G1Affine, error) {
+		return fwht.MatVecHadamardParAffine(p, w, -1)
+	}
+	outMix, stMix, err := runVariant(points, maxProcs, iters, fwht.MatVecHadamardParMixed)
+	must(err)
+	outAff, stAff, err := runVariant(points, maxProcs, iters, affine)
+	must(err)
+
+	fmt.Printf("-- Coordinates --\n")
+	fmt.Printf("Correctness (mixed, affine vs jacobian) : %v, %v\n", eqSlices(outR2, outMix), eqSlices(outR2, outAff))
+	fastest, fastestBest := "Jacobian", stR2.best
+	for _, v := range []struct {
+		name string
+		st   runStats
+	}{{"Jacobian", stR2}, {"Mixed", stMix}, {"Affine", stAff}} {
+		avg := time.Duration(int64(v.st.total) / int64(iters))
+		fmt.Printf("%-8s Best: %v | Avg: %v | Speedup vs Jacobian (best): %.2fx\n",
+			v.name, v.st.best, avg, float64(stR2.best)/float64(v.st.best))
+		if v.st.best < fastestBest {
+			fastest, fastestBest = v.name, v.st.best
+		}
+	}
+	fmt.Printf("Fastest at n=2^%d: %s\n\n", exp, fastest)
+
+	// 2D: row pass (M·Hᵀ), column pass (H·M, transpose-free), both (H·M·Hᵀ == flat FWHT)
+	rows, cols := 1<<(exp-exp/2), 1<<(exp/2)
+	pass := func(axes fwht.Axes2D) func([]bn254.G1Affine, int) ([]bn254.G1Affine, error) {
+		return func(p []bn254.G1Affine, w int) ([]bn254.G1Affine, error) {
+			return fwht.MatVecHadamard2DPar(p, rows, cols, axes, w)
+		}
+	}
+	outRow, stRow, err := runVariant(points, maxProcs, iters, pass(fwht.Axes2DRows))
+	must(err)
+	outCol, stCol, err := runVariant(outRow, maxProcs, iters, pass(fwht.Axes2DCols))
+	must(err)
+	out2D, st2D, err := runVariant(points, maxProcs, iters, pass(fwht.Axes2DBoth))
+	must(err)
+
+	fmt.Printf("-- 2D (%d x %d) --\n", rows, cols)
+	fmt.Printf("Correctness (cols∘rows, 2D vs flat) : %v, %v\n", eqSlices(outCol, out2D), eqSlices(out2D, outR2))
+	for _, v := range []struct {
+		name string
+		st   runStats
+	}{{"Rows", stRow}, {"Cols", stCol}, {"2D", st2D}} {
+		avg := time.Duration(int64(v.st.total) / int64(iters))
+		fmt.Printf("%-8s Best: %v | Avg: %v | Speedup vs flat (best): %.2fx\n",
+			v.name, v.st.best, avg, float64(stR2.best)/float64(v.st.best))
+	}
+	fmt.Println()
 }
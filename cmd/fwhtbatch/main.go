@@ -0,0 +1,142 @@
+// go run ./cmd/fwhtbatch <exp> <k> [workers]
+//
+//	Transforms k random vectors of lengths 2^0..2^exp (plus an empty one) with
+//	fwht.MatVecHadamardBatchPar, and k rows of 2^exp points laid out with stride
+//	2^exp+3 with fwht.MatVecHadamardStridedPar, checking every vector and row
+//	against its own MatVecHadamardPar (the gaps between rows must be untouched).
+//	Also checks that lengths which are not powers of two and bad strides are rejected.
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/fwhtbatch <exp> <k> [workers]")
+		fmt.Println("Example: go run ./cmd/fwhtbatch 10 8 4   # 8 vectors of up to 2^10 points")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	k, err := strconv.Atoi(os.Args[2])
+	if err != nil || k < 1 {
+		fmt.Printf("invalid k: %v\n", os.Args[2])
+		return
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(os.Args) >= 4 {
+		if w, err := strconv.Atoi(os.Args[3]); err == nil && w > 0 {
+			workers = w
+		}
+	}
+
+	// 길이가 서로 다른 벡터들: 빈 벡터, 2^exp 하나, 나머지는 임의의 2^e
+	vs := make([][]bn254.G1Affine, k+1)
+	for v := range vs {
+		size := 1 << rand.Intn(exp+1)
+		switch v {
+		case 0:
+			size = 0
+		case 1:
+			size = n
+		}
+		vs[v], err = randutil.RandomPointsG1Par(size, workers)
+		must(err)
+	}
+	start := time.Now()
+	got, err := fwht.MatVecHadamardBatchPar(vs, workers)
+	must(err)
+	fmt.Printf("MatVecHadamardBatchPar (%d vectors, up to %d points): %s\n", len(vs), n, time.Since(start))
+	if len(got) != len(vs) {
+		fmt.Printf("Check failed ❌ : %d outputs for %d vectors\n", len(got), len(vs))
+		return
+	}
+	for v := range vs {
+		want, err := fwht.MatVecHadamardPar(vs[v], workers)
+		must(err)
+		if !equal(got[v], want) {
+			fmt.Printf("Check failed ❌ : batch vector %d (len %d) != MatVecHadamardPar\n", v, len(vs[v]))
+			return
+		}
+	}
+
+	// stride = n+3 인 행렬: 행 사이의 3개 점은 그대로 남아야 함
+	stride := n + 3
+	m, err := randutil.RandomPointsG1Par(k*stride, workers)
+	must(err)
+	orig := append([]bn254.G1Affine(nil), m...)
+	start = time.Now()
+	must(fwht.MatVecHadamardStridedPar(m, n, stride, workers))
+	fmt.Printf("MatVecHadamardStridedPar (%d rows of %d, stride %d): %s\n", k, n, stride, time.Since(start))
+	for r := 0; r < k; r++ {
+		want, err := fwht.MatVecHadamardPar(orig[r*stride:r*stride+n], workers)
+		must(err)
+		if !equal(m[r*stride:r*stride+n], want) {
+			fmt.Printf("Check failed ❌ : strided row %d != MatVecHadamardPar\n", r)
+			return
+		}
+		if !equal(m[r*stride+n:(r+1)*stride], orig[r*stride+n:(r+1)*stride]) {
+			fmt.Printf("Check failed ❌ : strided row %d overwrote the gap after it\n", r)
+			return
+		}
+	}
+
+	// 거부되어야 하는 입력
+	bad := []struct {
+		label string
+		err   error
+	}{
+		{"batch with a vector of length 3", batchErr(append(vs[:2:2], make([]bn254.G1Affine, 3)))},
+		{"strided n = 3", fwht.MatVecHadamardStridedPar(make([]bn254.G1Affine, 8), 3, 4, workers)},
+		{"strided stride < n", fwht.MatVecHadamardStridedPar(make([]bn254.G1Affine, 8), 4, 2, workers)},
+		{"strided len(m) not a multiple of stride", fwht.MatVecHadamardStridedPar(make([]bn254.G1Affine, 10), 4, 4, workers)},
+	}
+	for _, b := range bad {
+		if b.err == nil {
+			fmt.Printf("Check failed ❌ : %s was accepted\n", b.label)
+			return
+		}
+	}
+	fmt.Println("Check passed ✅ : batched and strided vectors == MatVecHadamardPar, bad lengths rejected")
+}
+
+func batchErr(vs [][]bn254.G1Affine) error {
+	_, err := fwht.MatVecHadamardBatchPar(vs, 0)
+	return err
+}
+
+func equal(a, b []bn254.G1Affine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
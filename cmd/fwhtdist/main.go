@@ -0,0 +1,164 @@
+// go run ./cmd/fwhtdist local <exp> <procs> [workers]
+// go run ./cmd/fwhtdist coord <exp> <procs> <addr> [timeout]
+// go run ./cmd/fwhtdist worker <coordAddr> [workers] [listen] [advertise] [timeout]
+//
+//	local : spawns procs worker processes on 127.0.0.1 and coordinates them
+//	coord : listens on addr and waits for procs workers started by hand
+//	worker: one rank of the transform; its peer listener binds listen
+//	        (default 127.0.0.1:0) and is announced to the partners as advertise
+//	        (default the bound address; set it when listen is 0.0.0.0:port)
+//
+// Addresses are TCP host:port, or unix:/path for a Unix socket. timeout bounds the
+// whole run (default 10m) so a lost worker or partner fails it instead of hanging.
+// local and coord verify the gathered result against MatVecHadamardSerialInPlace.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+// defaultTimeout bounds coord and worker runs without a timeout argument.
+const defaultTimeout = 10 * time.Minute
+
+func main() {
+	if len(os.Args) < 3 {
+		usage()
+		return
+	}
+	switch os.Args[1] {
+	case "local":
+		if len(os.Args) < 4 {
+			usage()
+			return
+		}
+		workers := "0"
+		if len(os.Args) >= 5 {
+			workers = os.Args[4]
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		must(err)
+		defer ln.Close()
+
+		self, err := os.Executable()
+		must(err)
+		procs := atoi(os.Args[3])
+		cmds := make([]*exec.Cmd, procs)
+		for i := range cmds {
+			cmds[i] = exec.Command(self, "worker", ln.Addr().String(), workers)
+			cmds[i].Stdout, cmds[i].Stderr = os.Stdout, os.Stderr
+			must(cmds[i].Start())
+		}
+		coordinate(ln, atoi(os.Args[2]), procs, defaultTimeout)
+		for _, c := range cmds {
+			must(c.Wait())
+		}
+	case "coord":
+		if len(os.Args) < 5 {
+			usage()
+			return
+		}
+		ln, err := net.Listen(splitAddr(os.Args[4]))
+		must(err)
+		defer ln.Close()
+		fmt.Printf("coordinator listening on %s\n", ln.Addr())
+		coordinate(ln, atoi(os.Args[2]), atoi(os.Args[3]), argTimeout(5))
+	case "worker":
+		opts := fwht.DistWorkerOptions{}
+		if len(os.Args) >= 4 {
+			opts.Workers = atoi(os.Args[3])
+		}
+		listen := "127.0.0.1:0"
+		if len(os.Args) >= 5 && os.Args[4] != "" {
+			listen = os.Args[4]
+		}
+		ln, err := net.Listen(splitAddr(listen))
+		must(err)
+		defer ln.Close()
+		if len(os.Args) >= 6 && os.Args[5] != "" {
+			_, opts.Advertise = splitAddr(os.Args[5])
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), argTimeout(6))
+		defer cancel()
+		network, coordAddr := splitAddr(os.Args[2])
+		must(fwht.RunDistWorker(ctx, network, coordAddr, ln, opts))
+	default:
+		usage()
+	}
+}
+
+// splitAddr maps "unix:/path" to ("unix", "/path") and anything else to TCP.
+func splitAddr(s string) (network, addr string) {
+	if path, ok := strings.CutPrefix(s, "unix:"); ok {
+		return "unix", path
+	}
+	return "tcp", s
+}
+
+// argTimeout parses os.Args[i] as a duration (default defaultTimeout).
+func argTimeout(i int) time.Duration {
+	if len(os.Args) <= i {
+		return defaultTimeout
+	}
+	d, err := time.ParseDuration(os.Args[i])
+	must(err)
+	return d
+}
+
+// coordinate runs MatVecHadamardDist on 2^exp random points and checks it.
+func coordinate(ln net.Listener, exp, procs int, timeout time.Duration) {
+	n := 1 << exp
+	input, err := randutil.RandomPointsG1Par(n, runtime.GOMAXPROCS(0))
+	must(err)
+
+	fmt.Printf("Running distributed FWHT with n = 2^%d = %d points, procs = %d\n", exp, n, procs)
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := fwht.MatVecHadamardDist(ctx, ln, input, procs)
+	must(err)
+	fmt.Printf("MatVecHadamardDist done in %s\n", time.Since(start))
+
+	want := append([]bn254.G1Affine(nil), input...)
+	must(fwht.MatVecHadamardSerialInPlace(want))
+	for i := range want {
+		if !out[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : mismatch at index %d\n", i)
+			os.Exit(1)
+		}
+	}
+	fmt.Println("Check passed ✅ : MatVecHadamardDist == MatVecHadamardSerialInPlace")
+}
+
+func usage() {
+	fmt.Println("Usage: go run ./cmd/fwhtdist local <exp> <procs> [workers]")
+	fmt.Println("       go run ./cmd/fwhtdist coord <exp> <procs> <addr> [timeout]")
+	fmt.Println("       go run ./cmd/fwhtdist worker <coordAddr> [workers] [listen] [advertise] [timeout]")
+	fmt.Println("Example: go run ./cmd/fwhtdist local 12 4   # 2^12 points over 4 local processes")
+	fmt.Println("Example: go run ./cmd/fwhtdist worker 10.0.0.1:7000 0 0.0.0.0:7001 10.0.0.2:7001")
+	fmt.Println("Example: go run ./cmd/fwhtdist worker unix:/tmp/coord.sock 0 unix:/tmp/w0.sock")
+}
+
+func atoi(s string) int {
+	v, err := strconv.Atoi(s)
+	must(err)
+	return v
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
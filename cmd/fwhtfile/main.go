@@ -0,0 +1,152 @@
+// go run ./cmd/fwhtfile <exp> <workers> [chunk]
+//
+//	Writes 2^exp random points to a point file, runs fwht.MatVecHadamardFile on it
+//	with a chunk of `chunk` points (default 4, so every pass is a strided one) and
+//	checks the result against MatVecHadamardPar. The run is then repeated with
+//	MatVecHadamardFileCtx cancelled after every pass and resumed from the checkpoint.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/fwhtfile <exp> <workers> [chunk]")
+		fmt.Println("Example: go run ./cmd/fwhtfile 10 4 8   # 2^10 points, 8 points in memory")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	chunk := 4
+	if len(os.Args) >= 4 {
+		chunk, err = strconv.Atoi(os.Args[3])
+		if err != nil || chunk < 2 {
+			fmt.Printf("invalid chunk: %v\n", os.Args[3])
+			return
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "fwhtfile")
+	must(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "points.bin")
+	opts := fwht.FileOptions{Workers: workers, ChunkPoints: chunk}
+
+	g, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	want, err := fwht.MatVecHadamardPar(g, workers)
+	must(err)
+
+	// 파일 왕복
+	must(fwht.WritePointsFile(path, g))
+	back, err := fwht.ReadPointsFile(path)
+	must(err)
+	if !equal(back, g) {
+		fmt.Println("Check failed ❌ : ReadPointsFile(WritePointsFile(G)) != G")
+		return
+	}
+
+	// 한 번에 끝까지
+	start := time.Now()
+	must(fwht.MatVecHadamardFile(path, opts))
+	fmt.Printf("MatVecHadamardFile (n=%d, chunk=%d): %s\n", n, chunk, time.Since(start))
+	if !checkResult(path, want, "MatVecHadamardFile") {
+		return
+	}
+
+	// 매 pass 후 취소하고 checkpoint 에서 재개
+	must(fwht.WritePointsFile(path, g))
+	runs, lastStage := 0, -1
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := fwht.MatVecHadamardFileCtx(ctx, path, opts, func(stage int, done int64) {
+			if stage <= lastStage {
+				fmt.Printf("Check failed ❌ : resumed run repeated stage %d\n", stage)
+				os.Exit(1)
+			}
+			lastStage = stage
+			cancel()
+		})
+		cancel()
+		runs++
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, context.Canceled) {
+			must(err)
+		}
+		if _, err := os.Stat(path + ".ckpt"); err != nil {
+			fmt.Printf("Check failed ❌ : no checkpoint after cancelled run %d: %v\n", runs, err)
+			return
+		}
+	}
+	fmt.Printf("MatVecHadamardFileCtx: finished after %d runs\n", runs)
+	if lastStage != exp-1 && exp > 0 {
+		fmt.Printf("Check failed ❌ : last reported stage %d, want %d\n", lastStage, exp-1)
+		return
+	}
+	if !checkResult(path, want, "resumed MatVecHadamardFileCtx") {
+		return
+	}
+	fmt.Println("Check passed ✅ : MatVecHadamardFile == MatVecHadamardPar, also when resumed after every pass")
+}
+
+// checkResult compares the point file at path with want and checks that the
+// .tmp and .ckpt helpers were removed.
+func checkResult(path string, want []bn254.G1Affine, label string) bool {
+	got, err := fwht.ReadPointsFile(path)
+	must(err)
+	if !equal(got, want) {
+		fmt.Printf("Check failed ❌ : %s != MatVecHadamardPar\n", label)
+		return false
+	}
+	for _, p := range []string{path + ".tmp", path + ".ckpt"} {
+		if _, err := os.Stat(p); !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("Check failed ❌ : %s left %s behind\n", label, filepath.Base(p))
+			return false
+		}
+	}
+	return true
+}
+
+func equal(a, b []bn254.G1Affine) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
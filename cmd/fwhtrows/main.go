@@ -0,0 +1,119 @@
+// go run ./cmd/fwhtrows <exp> <workers> <k>
+//
+//	Evaluates k random rows of H·G with fwht.HadamardRowsPar and checks them
+//	against the full MatVecHadamardPar output, then checks the fused
+//	Agg = Σ R_i·(H[idx_i]·G) of msm.HadamardRowsRLC against the row-by-row path
+//	and that it rejects a length which is not a power of two.
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/msm"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: go run ./cmd/fwhtrows <exp> <workers> <k>")
+		fmt.Println("Example: go run ./cmd/fwhtrows 10 4 18   # 18 rows of a 2^10 transform")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp <= 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	k, err := strconv.Atoi(os.Args[3])
+	if err != nil || k <= 0 {
+		fmt.Printf("invalid k: %v\n", os.Args[3])
+		return
+	}
+
+	fmt.Printf("Evaluating %d rows of H·G with n = 2^%d = %d, workers = %d\n", k, exp, n, workers)
+
+	g, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+
+	rows := make([]int, k)
+	for i := range rows {
+		rows[i] = rand.Intn(n)
+	}
+	if k > 1 {
+		rows[k-1] = rows[0] // 중복 행도 처리되는지 확인
+	}
+
+	start := time.Now()
+	ys, err := fwht.HadamardRowsPar(g, rows, workers)
+	must(err)
+	fmt.Printf("HadamardRowsPar done in %s\n", time.Since(start))
+
+	start = time.Now()
+	full, err := fwht.MatVecHadamardPar(g, workers)
+	must(err)
+	fmt.Printf("MatVecHadamardPar done in %s\n", time.Since(start))
+
+	for t, r := range rows {
+		if !ys[t].Equal(&full[r]) {
+			fmt.Printf("Check failed ❌ : row %d (index %d) mismatch\n", r, t)
+			return
+		}
+	}
+	fmt.Println("Check passed ✅ : HadamardRowsPar == MatVecHadamardPar rows")
+
+	// Agg = Σ R_i·Y_i: row-by-row vs one fused MSM
+	r, err := randutil.RandomScalarsPar(k, workers)
+	must(err)
+
+	start = time.Now()
+	var aggJac bn254.G1Jac
+	for i := range rows {
+		var term bn254.G1Jac
+		term.FromAffine(&ys[i])
+		term.ScalarMultiplication(&term, r[i].BigInt(new(big.Int)))
+		aggJac.AddAssign(&term)
+	}
+	var rowByRow bn254.G1Affine
+	rowByRow.FromJacobian(&aggJac)
+	fmt.Printf("Row-by-row Agg done in %s\n", time.Since(start))
+
+	start = time.Now()
+	fused, err := msm.HadamardRowsRLC(g, rows, r)
+	must(err)
+	fmt.Printf("HadamardRowsRLC done in %s\n", time.Since(start))
+
+	if !fused.Equal(&rowByRow) {
+		fmt.Println("Check failed ❌ : HadamardRowsRLC != row-by-row Agg")
+		return
+	}
+
+	// 길이가 2의 거듭제곱이 아니면 거부
+	if _, err := msm.HadamardRowsRLC(make([]bn254.G1Affine, 3), []int{0}, r[:1]); err == nil {
+		fmt.Println("Check failed ❌ : HadamardRowsRLC accepted 3 points")
+		return
+	}
+	fmt.Println("Check passed ✅ : HadamardRowsRLC == row-by-row Agg, non-power-of-two length rejected")
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,337 @@
+// go run ./cmd/fwhtscalar <exp> <workers>
+//
+//	Cross-checks the scalar FWHT against the group FWHT: H·(s·G) == (H·s)·G,
+//	that the inverse/scaled scalar transforms round-trip, the output orderings, and
+//	the pad policies (PadInfo included) against manually padded inputs, and the
+//	row/column passes of the 2D scalar transform against the flat one.
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/fwhtscalar <exp> <workers>")
+		fmt.Println("Example: go run ./cmd/fwhtscalar 10 4   # n = 2^10 scalars, workers = 4")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp <= 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	fmt.Printf("Running scalar FWHT with n = 2^%d = %d, workers = %d\n", exp, n, workers)
+
+	s, err := randutil.RandomScalarsPar(n, workers)
+	must(err)
+
+	// points[i] = s[i]·G
+	_, _, g, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, n)
+	for i := 0; i < n; i++ {
+		points[i].ScalarMultiplication(&g, s[i].BigInt(new(big.Int)))
+	}
+
+	// H·s (parallel) and H·s (serial) must agree
+	hs := append([]fr.Element(nil), s...)
+	start := time.Now()
+	must(fwht.MatVecHadamardFrParInPlace(hs, workers))
+	fmt.Printf("Fr FWHT (par) done in %s\n", time.Since(start))
+
+	hsSerial := append([]fr.Element(nil), s...)
+	start = time.Now()
+	must(fwht.MatVecHadamardFrSerialInPlace(hsSerial))
+	fmt.Printf("Fr FWHT (serial) done in %s\n", time.Since(start))
+
+	ok := true
+	for i := 0; i < n; i++ {
+		if !hs[i].Equal(&hsSerial[i]) {
+			fmt.Printf("Fr par/serial mismatch at index %d\n", i)
+			ok = false
+			break
+		}
+	}
+
+	// H·(s·G) == (H·s)·G
+	hp, err := fwht.MatVecHadamardPar(points, workers)
+	must(err)
+	for i := 0; ok && i < n; i++ {
+		var expect bn254.G1Affine
+		expect.ScalarMultiplication(&g, hs[i].BigInt(new(big.Int)))
+		if !hp[i].Equal(&expect) {
+			fmt.Printf("H·(s·G) != (H·s)·G at index %d\n", i)
+			ok = false
+		}
+	}
+
+	// H⁻¹(H·s) == s, S(S(s)) == s
+	inv := append([]fr.Element(nil), hs...)
+	must(fwht.InvMatVecHadamardFrParInPlace(inv, workers))
+	sc := append([]fr.Element(nil), s...)
+	must(fwht.ScaledMatVecHadamardFrParInPlace(sc, workers))
+	must(fwht.ScaledMatVecHadamardFrParInPlace(sc, workers))
+	for i := 0; ok && i < n; i++ {
+		if !inv[i].Equal(&s[i]) || !sc[i].Equal(&s[i]) {
+			fmt.Printf("Fr inverse/scaled round trip mismatch at index %d\n", i)
+			ok = false
+		}
+	}
+
+	// Orderings: the group and scalar paths agree, and sequency/dyadic rows have
+	// k / GrayDecode(k) sign changes.
+	for _, order := range []fwht.Ordering{fwht.OrderSequency, fwht.OrderDyadic} {
+		ordered := append([]fr.Element(nil), s...)
+		must(fwht.MatVecHadamardFrInPlaceOpts(ordered, fwht.Options{Workers: workers, Order: order}))
+		op, err := fwht.MatVecHadamardParOpts(points, fwht.Options{Workers: workers, Order: order})
+		must(err)
+		for i := 0; ok && i < n; i++ {
+			var expect bn254.G1Affine
+			expect.ScalarMultiplication(&g, ordered[i].BigInt(new(big.Int)))
+			if !op[i].Equal(&expect) {
+				fmt.Printf("ordering %d: group/scalar mismatch at index %d\n", order, i)
+				ok = false
+			}
+		}
+		if ok {
+			ok = checkSignChanges(min(exp, 6), order, workers)
+		}
+	}
+
+	if ok {
+		ok = checkPadding(points, s, g, workers)
+	}
+	if ok {
+		ok = check2DField(s, hs, exp, workers)
+	}
+
+	// Fp: H(H(x)) == n·x
+	x := make([]fp.Element, n)
+	for i := range x {
+		x[i].SetRandom()
+	}
+	y := append([]fp.Element(nil), x...)
+	must(fwht.MatVecHadamardFpParInPlace(y, workers))
+	must(fwht.MatVecHadamardFpSerialInPlace(y))
+	var nFp fp.Element
+	nFp.SetUint64(uint64(n))
+	for i := 0; ok && i < n; i++ {
+		var expect fp.Element
+		expect.Mul(&x[i], &nFp)
+		if !y[i].Equal(&expect) {
+			fmt.Printf("Fp H(H(x)) != n·x at index %d\n", i)
+			ok = false
+		}
+	}
+
+	if ok {
+		fmt.Println("Check passed ✅ : H·(s·G) == (H·s)·G, H⁻¹(H·s) == s, S(S(s)) == s, orderings, padding, 2D and H(H(x)) == n·x over Fp")
+	} else {
+		fmt.Println("Check failed ❌")
+	}
+}
+
+// checkPadding transforms the first m = n/2+1 points and scalars (not a power of
+// two for n >= 4) under each pad policy and compares the output and PadInfo with
+// MatVecHadamardPar / MatVecHadamardFrParInPlace of the input padded by hand.
+func checkPadding(points []bn254.G1Affine, s []fr.Element, g bn254.G1Affine, workers int) bool {
+	n, m := len(points), len(points)/2+1
+	in := points[:m]
+
+	// 수동 패딩: ∞ (0,0) 또는 g 로 채움
+	padded := func(fill bn254.G1Affine) []bn254.G1Affine {
+		p := make([]bn254.G1Affine, n)
+		copy(p, in)
+		for i := m; i < n; i++ {
+			p[i] = fill
+		}
+		out, err := fwht.MatVecHadamardPar(p, workers)
+		must(err)
+		return out
+	}
+	wantZero, wantG := padded(bn254.G1Affine{}), padded(g)
+
+	cases := []struct {
+		label string
+		opts  fwht.Options
+		want  []bn254.G1Affine
+	}{
+		{"PadIdentity", fwht.Options{Workers: workers, Pad: fwht.PadIdentity}, wantZero},
+		{"PadTruncate", fwht.Options{Workers: workers, Pad: fwht.PadTruncate}, wantZero[:m]},
+		{"PadPoint", fwht.Options{Workers: workers, Pad: fwht.PadPoint, PadWith: g}, wantG},
+		{"PadPoint (*G1Affine)", fwht.Options{Workers: workers, Pad: fwht.PadPoint, PadWith: &g}, wantG},
+	}
+	for _, c := range cases {
+		out, info, err := fwht.MatVecHadamardParPad(in, c.opts)
+		must(err)
+		if want := (fwht.PadInfo{InputLen: m, PaddedLen: n, OutputLen: len(c.want)}); info != want {
+			fmt.Printf("%s: PadInfo %+v, want %+v\n", c.label, info, want)
+			return false
+		}
+		if len(out) != len(c.want) {
+			fmt.Printf("%s: %d rows, want %d\n", c.label, len(out), len(c.want))
+			return false
+		}
+		for i := range out {
+			if !out[i].Equal(&c.want[i]) {
+				fmt.Printf("%s: mismatch with the padded MatVecHadamardPar at index %d\n", c.label, i)
+				return false
+			}
+		}
+	}
+	if _, _, err := fwht.MatVecHadamardParPad(in, fwht.Options{Workers: workers, Pad: fwht.PadPoint, PadWith: s[0]}); err == nil {
+		fmt.Println("PadPoint with an fr.Element PadWith was accepted")
+		return false
+	}
+
+	// Fr: PadTruncate == 0 으로 채운 벡터의 앞 m 행 (정규화도 패딩된 길이 n 기준)
+	for _, norm := range []fwht.Normalization{fwht.NormNone, fwht.NormInverse} {
+		p := make([]fr.Element, n)
+		copy(p, s[:m])
+		must(fwht.GenericMatVecHadamardFieldInPlaceOpts(p, fr.Modulus(), fwht.Options{Workers: workers, Norm: norm}))
+		v := append([]fr.Element(nil), s[:m]...)
+		must(fwht.MatVecHadamardFrInPlaceOpts(v, fwht.Options{Workers: workers, Norm: norm, Pad: fwht.PadTruncate}))
+		for i := range v {
+			if !v[i].Equal(&p[i]) {
+				fmt.Printf("Fr PadTruncate (norm %d): mismatch with the padded transform at index %d\n", norm, i)
+				return false
+			}
+		}
+	}
+	for _, pad := range []fwht.PadPolicy{fwht.PadIdentity, fwht.PadPoint} {
+		v := append([]fr.Element(nil), s[:m]...)
+		if err := fwht.MatVecHadamardFrInPlaceOpts(v, fwht.Options{Workers: workers, Pad: pad}); err == nil {
+			fmt.Printf("Fr pad policy %d was accepted in place\n", pad)
+			return false
+		}
+	}
+	if m&(m-1) != 0 {
+		v := append([]fr.Element(nil), s[:m]...)
+		if err := fwht.MatVecHadamardFrInPlaceOpts(v, fwht.Options{Workers: workers}); err == nil {
+			fmt.Println("Fr PadError accepted a non-power-of-two length")
+			return false
+		}
+		if _, _, err := fwht.MatVecHadamardParPad(in, fwht.Options{Workers: workers}); err == nil {
+			fmt.Println("PadError accepted a non-power-of-two length")
+			return false
+		}
+	}
+	return true
+}
+
+// check2DField views s as a 2^ceil(exp/2) × 2^floor(exp/2) matrix and checks that
+// the column pass after the row pass of MatVecHadamard2DFrParInPlace equals
+// Axes2DBoth, which equals the flat transform hs, also over Fp through
+// GenericMatVecHadamard2DFieldParInPlace. Shapes that are not powers of two or do
+// not match len(m) must be rejected.
+func check2DField(s, hs []fr.Element, exp, workers int) bool {
+	rows, cols := 1<<(exp-exp/2), 1<<(exp/2)
+
+	// Cols∘Rows
+	rc := append([]fr.Element(nil), s...)
+	must(fwht.MatVecHadamard2DFrParInPlace(rc, rows, cols, fwht.Axes2DRows, workers))
+	must(fwht.MatVecHadamard2DFrParInPlace(rc, rows, cols, fwht.Axes2DCols, workers))
+	both := append([]fr.Element(nil), s...)
+	must(fwht.MatVecHadamard2DFrParInPlace(both, rows, cols, fwht.Axes2DBoth, workers))
+	for i := range s {
+		if !rc[i].Equal(&both[i]) || !both[i].Equal(&hs[i]) {
+			fmt.Printf("Fr 2D (%d x %d): cols∘rows / both / flat mismatch at index %d\n", rows, cols, i)
+			return false
+		}
+	}
+
+	// Fp: generic 2D == flat
+	x := make([]fp.Element, len(s))
+	for i := range x {
+		x[i].SetRandom()
+	}
+	flat := append([]fp.Element(nil), x...)
+	must(fwht.MatVecHadamardFpParInPlace(flat, workers))
+	must(fwht.GenericMatVecHadamard2DFieldParInPlace(x, rows, cols, fwht.Axes2DBoth, workers))
+	for i := range x {
+		if !x[i].Equal(&flat[i]) {
+			fmt.Printf("Fp 2D (%d x %d) != flat at index %d\n", rows, cols, i)
+			return false
+		}
+	}
+
+	// 거부되어야 하는 모양
+	bad := []struct {
+		label      string
+		rows, cols int
+		m          []fr.Element
+	}{
+		{"rows = 3", 3, cols, make([]fr.Element, 3*cols)},
+		{"cols = 3", rows, 3, make([]fr.Element, rows*3)},
+		{"rows = 0", 0, cols, nil},
+		{"rows*cols != len(m)", rows, cols, make([]fr.Element, 2*len(s))},
+	}
+	for _, b := range bad {
+		if err := fwht.MatVecHadamard2DFrParInPlace(b.m, b.rows, b.cols, fwht.Axes2DBoth, workers); err == nil {
+			fmt.Printf("Fr 2D accepted %s\n", b.label)
+			return false
+		}
+	}
+	return true
+}
+
+// checkSignChanges builds the ordered 2^logN Hadamard matrix column by column
+// (transforming unit vectors) and checks the sign changes of every row.
+func checkSignChanges(logN int, order fwht.Ordering, workers int) bool {
+	n := 1 << logN
+	rows := make([][]fr.Element, n)
+	for k := range rows {
+		rows[k] = make([]fr.Element, n)
+	}
+	for j := 0; j < n; j++ {
+		e := make([]fr.Element, n)
+		e[j].SetOne()
+		must(fwht.MatVecHadamardFrInPlaceOpts(e, fwht.Options{Workers: workers, Order: order}))
+		for k := 0; k < n; k++ {
+			rows[k][j] = e[k]
+		}
+	}
+	for k := 0; k < n; k++ {
+		changes := 0
+		for j := 1; j < n; j++ {
+			if !rows[k][j].Equal(&rows[k][j-1]) {
+				changes++
+			}
+		}
+		want := k
+		if order == fwht.OrderDyadic {
+			want = fwht.GrayDecode(k)
+		}
+		if changes != want {
+			fmt.Printf("ordering %d: row %d has %d sign changes, want %d\n", order, k, changes, want)
+			return false
+		}
+	}
+	return true
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,146 @@
+// go run ./cmd/fwhtstages <exp> <workers>
+//
+//	For several stage masks, checks fwht.MatVecHadamardStagesPar and
+//	MatVecHadamardFrStagesParInPlace on 2^exp random inputs against the direct sums
+//	Σ_j PartialHadamardEntry(i, j, mask)·x_j, mask = all stages against
+//	MatVecHadamardPar, and msm.HadamardRowsRLCStages (the native side of the row
+//	circuit's Stages mask) against the rows of MatVecHadamardStagesPar.
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/msm"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/fwhtstages <exp> <workers>")
+		fmt.Println("Example: go run ./cmd/fwhtstages 8 4   # 2^8 points (direct sums are O(n²))")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 1 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	g, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	s, err := randutil.RandomScalarsPar(n, workers)
+	must(err)
+
+	// mask: 없음, 최하위 stage, 하위 절반, 상위 절반, 짝수 stage, 임의, 전체
+	all := uint64(n - 1)
+	masks := []uint64{
+		0,
+		fwht.StageMask(0),
+		1<<(exp/2) - 1,
+		all &^ (1<<(exp/2) - 1),
+		0x5555555555555555 & all,
+		rand.Uint64() & all,
+		all,
+	}
+	for _, mask := range masks {
+		got, err := fwht.MatVecHadamardStagesPar(g, mask, workers)
+		must(err)
+		v := append([]fr.Element(nil), s...)
+		must(fwht.MatVecHadamardFrStagesParInPlace(v, mask, workers))
+		for i := 0; i < n; i++ {
+			var acc bn254.G1Jac
+			var sum fr.Element
+			for j := 0; j < n; j++ {
+				switch fwht.PartialHadamardEntry(i, j, mask) {
+				case 1:
+					acc.AddMixed(&g[j])
+					sum.Add(&sum, &s[j])
+				case -1:
+					var neg bn254.G1Affine
+					neg.Neg(&g[j])
+					acc.AddMixed(&neg)
+					sum.Sub(&sum, &s[j])
+				}
+			}
+			var want bn254.G1Affine
+			want.FromJacobian(&acc)
+			if !got[i].Equal(&want) || !v[i].Equal(&sum) {
+				fmt.Printf("Check failed ❌ : mask %#x: row %d != Σ_j PartialHadamardEntry·x_j\n", mask, i)
+				return
+			}
+		}
+		if !checkRLC(g, got, mask, workers) {
+			return
+		}
+	}
+
+	// mask = 전체 stage 는 MatVecHadamardPar 와 같아야 함
+	full, err := fwht.MatVecHadamardStagesPar(g, all, workers)
+	must(err)
+	want, err := fwht.MatVecHadamardPar(g, workers)
+	must(err)
+	for i := range want {
+		if !full[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : mask = all stages != MatVecHadamardPar at index %d\n", i)
+			return
+		}
+	}
+
+	// log2(n) 이상의 stage 를 고르는 mask 는 거부
+	if _, err := fwht.MatVecHadamardStagesPar(g, 1<<uint(exp), workers); err == nil {
+		fmt.Printf("Check failed ❌ : mask %#x accepted for n = %d\n", uint64(1)<<uint(exp), n)
+		return
+	}
+	fmt.Printf("Check passed ✅ : %d stage masks == direct PartialHadamardEntry sums, all stages == MatVecHadamardPar, HadamardRowsRLCStages == Σ r_i·rows\n", len(masks))
+}
+
+// checkRLC compares msm.HadamardRowsRLCStages for a few random rows with
+// Σ r_i·rows[i] of the transform out computed with the same mask.
+func checkRLC(g, out []bn254.G1Affine, mask uint64, workers int) bool {
+	k := 4
+	rows := make([]int, k)
+	for i := range rows {
+		rows[i] = rand.Intn(len(g))
+	}
+	r, err := randutil.RandomScalarsPar(k, workers)
+	must(err)
+	got, err := msm.HadamardRowsRLCStages(g, rows, r, mask)
+	must(err)
+
+	var acc bn254.G1Jac
+	for i, row := range rows {
+		var t bn254.G1Jac
+		t.FromAffine(&out[row])
+		t.ScalarMultiplication(&t, r[i].BigInt(new(big.Int)))
+		acc.AddAssign(&t)
+	}
+	var want bn254.G1Affine
+	want.FromJacobian(&acc)
+	if !got.Equal(&want) {
+		fmt.Printf("Check failed ❌ : mask %#x: HadamardRowsRLCStages != Σ r_i·rows\n", mask)
+		return false
+	}
+	return true
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -81,7 +81,9 @@
 package main
 
 import (
+	"context"
 	// "crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
 	"os"
@@ -90,16 +92,45 @@ import (
 	"strings"
 	"time"
 
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	bw6761fr "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 
 	"github.com/Han-16/fwhtist/internal/fwht"
 )
 
+// point is the affine API the check needs, shared by every gnark-crypto G1Affine/G2Affine.
+type point[A any] interface {
+	*A
+	ScalarMultiplication(*A, *big.Int) *A
+	Equal(*A) bool
+}
+
+// transforms bundles the forward, inverse and scaled transforms of one group.
+type transforms[A any] struct {
+	fwd, inv, scaled func([]A, int) ([]A, error)
+}
+
+// normalized returns the generic transform with norm applied, over a group of order r.
+func normalized[A, J any, PA fwht.AffPoint[A, J], PJ fwht.JacPoint[J, A]](r *big.Int, norm fwht.Normalization) func([]A, int) ([]A, error) {
+	return func(in []A, workers int) ([]A, error) {
+		return fwht.GenericMatVecHadamardParOpts[A, J, PA, PJ](in, r, fwht.Options{Workers: workers, Norm: norm})
+	}
+}
+
+var curves = []string{"bn254", "bn254-g2", "bls12-381", "bls12-377", "bw6-761"}
+
 func main() {
 	if len(os.Args) < 4 {
-		fmt.Println("Usage: go run ./cmd/fwhtverify <exp> <workers> <mode>")
+		fmt.Println("Usage: go run ./cmd/fwhtverify <exp> <workers> <mode> [curve]")
 		fmt.Println("Example: go run ./cmd/fwhtverify 10 4 const   # n = 2^10 points, const input")
 		fmt.Println("Example: go run ./cmd/fwhtverify 10 4 rand    # n = 2^10 points, random input")
+		fmt.Println("Example: go run ./cmd/fwhtverify 10 4 rand all  # every curve (bn254, bn254-g2, bls12-381, bls12-377, bw6-761)")
 		return
 	}
 
@@ -124,11 +155,66 @@ func main() {
 		return
 	}
 
-	fmt.Printf("Running FWHT with n = 2^%d = %d points, workers = %d, mode=%s\n", exp, n, workers, mode)
+	// curve 파싱 (기본 bn254)
+	selected := []string{"bn254"}
+	if len(os.Args) >= 5 {
+		c := strings.ToLower(os.Args[4])
+		if c == "all" {
+			selected = curves
+		} else {
+			selected = []string{c}
+		}
+	}
+
+	for _, c := range selected {
+		switch c {
+		case "bn254":
+			_, _, g, _ := bn254.Generators()
+			check(c, g, transforms[bn254.G1Affine]{fwht.MatVecHadamardPar, fwht.InvMatVecHadamardPar, fwht.ScaledMatVecHadamardPar}, n, exp, workers, mode)
+			checkInPlace(g, n, workers)
+			checkCtx(g, n, exp, workers)
+		case "bn254-g2":
+			_, _, _, g := bn254.Generators()
+			check(c, g, transforms[bn254.G2Affine]{
+				fwht.MatVecHadamardParG2,
+				normalized[bn254.G2Affine, bn254.G2Jac](fr.Modulus(), fwht.NormInverse),
+				normalized[bn254.G2Affine, bn254.G2Jac](fr.Modulus(), fwht.NormOrthonormal),
+			}, n, exp, workers, mode)
+		case "bls12-381":
+			_, _, g, _ := bls12381.Generators()
+			check(c, g, transforms[bls12381.G1Affine]{
+				fwht.MatVecHadamardParBLS12381,
+				normalized[bls12381.G1Affine, bls12381.G1Jac](bls12381fr.Modulus(), fwht.NormInverse),
+				normalized[bls12381.G1Affine, bls12381.G1Jac](bls12381fr.Modulus(), fwht.NormOrthonormal),
+			}, n, exp, workers, mode)
+		case "bls12-377":
+			_, _, g, _ := bls12377.Generators()
+			check(c, g, transforms[bls12377.G1Affine]{
+				fwht.MatVecHadamardParBLS12377,
+				normalized[bls12377.G1Affine, bls12377.G1Jac](bls12377fr.Modulus(), fwht.NormInverse),
+				normalized[bls12377.G1Affine, bls12377.G1Jac](bls12377fr.Modulus(), fwht.NormOrthonormal),
+			}, n, exp, workers, mode)
+		case "bw6-761":
+			_, _, g, _ := bw6761.Generators()
+			check(c, g, transforms[bw6761.G1Affine]{
+				fwht.MatVecHadamardParBW6761,
+				normalized[bw6761.G1Affine, bw6761.G1Jac](bw6761fr.Modulus(), fwht.NormInverse),
+				normalized[bw6761.G1Affine, bw6761.G1Jac](bw6761fr.Modulus(), fwht.NormOrthonormal),
+			}, n, exp, workers, mode)
+		default:
+			fmt.Printf("unknown curve %q (want one of %v or all)\n", c, curves)
+			return
+		}
+	}
+}
+
+// check runs the transforms of one curve and verifies H(H(x)) = n * x,
+// H⁻¹(H(x)) = x and S(S(x)) = x for the scaled transform S = H/√n.
+func check[A any, PA point[A]](curve string, g1Aff A, tr transforms[A], n, exp, workers int, mode string) {
+	fmt.Printf("Running FWHT with n = 2^%d = %d points, workers = %d, mode=%s, curve=%s\n", exp, n, workers, mode, curve)
 
 	// 입력 벡터 준비
-	_, _, g1Aff, _ := bn254.Generators()
-	input := make([]bn254.G1Affine, n)
+	input := make([]A, n)
 
 	switch mode {
 	case "const":
@@ -141,14 +227,14 @@ func main() {
 		for i := 0; i < n; i++ {
 			// k, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 256))
 			// input[i].ScalarMultiplication(&g1Aff, k)
-			input[i].ScalarMultiplication(&g1Aff, big.NewInt(int64(i+1)))
+			PA(&input[i]).ScalarMultiplication(&g1Aff, big.NewInt(int64(i+1)))
 		}
 	}
 
 	// FWHT 실행
 	fmt.Printf("Starting FWHT...\n")
 	start := time.Now()
-	out, err := fwht.MatVecHadamardPar(input, workers)
+	out, err := tr.fwd(input, workers)
 	if err != nil {
 		fmt.Printf("FWHT failed: %v\n", err)
 		return
@@ -156,7 +242,7 @@ func main() {
 	fmt.Printf("FWHT done in %s (len=%d)\n", time.Since(start), len(out))
 
 	// 이중 FWHT 검증: H(H(x)) = n * x
-	out2, err := fwht.MatVecHadamardPar(out, workers)
+	out2, err := tr.fwd(out, workers)
 	if err != nil {
 		fmt.Printf("FWHT second run failed: %v\n", err)
 		return
@@ -164,17 +250,204 @@ func main() {
 
 	ok := true
 	for i := 0; i < n; i++ {
-		var expect bn254.G1Affine
-		expect.ScalarMultiplication(&input[i], big.NewInt(int64(n)))
-		if !out2[i].Equal(&expect) {
+		var expect A
+		PA(&expect).ScalarMultiplication(&input[i], big.NewInt(int64(n)))
+		if !PA(&out2[i]).Equal(&expect) {
 			fmt.Printf("Mismatch at index %d\n", i)
 			ok = false
 			break
 		}
 	}
+
+	// 역변환 / scaled 왕복 검증
 	if ok {
-		fmt.Println("Check passed ✅ : FWHT(FWHT(x)) == n * x for all elements")
+		ok = roundTrip[A, PA]("H⁻¹(H(x))", tr.fwd, tr.inv, input, workers) &&
+			roundTrip[A, PA]("S(S(x))", tr.scaled, tr.scaled, input, workers)
+	}
+
+	if ok {
+		fmt.Printf("Check passed ✅ : FWHT(FWHT(x)) == n * x, H⁻¹(H(x)) == x, S(S(x)) == x for all elements (%s)\n", curve)
 	} else {
-		fmt.Println("Check failed ❌ : some elements mismatch")
+		fmt.Printf("Check failed ❌ : some elements mismatch (%s)\n", curve)
+	}
+}
+
+// roundTrip checks dec(enc(x)) == x.
+func roundTrip[A any, PA point[A]](label string, enc, dec func([]A, int) ([]A, error), x []A, workers int) bool {
+	y, err := enc(x, workers)
+	if err == nil {
+		y, err = dec(y, workers)
+	}
+	if err != nil {
+		fmt.Printf("%s failed: %v\n", label, err)
+		return false
+	}
+	for i := range x {
+		if !PA(&y[i]).Equal(&x[i]) {
+			fmt.Printf("%s mismatch at index %d\n", label, i)
+			return false
+		}
+	}
+	return true
+}
+
+// checkInPlace compares the buffer-reusing variants against MatVecHadamardPar.
+func checkInPlace(g bn254.G1Affine, n, workers int) {
+	input := make([]bn254.G1Affine, n)
+	for i := 0; i < n; i++ {
+		input[i].ScalarMultiplication(&g, big.NewInt(int64(i+1)))
+	}
+	want, err := fwht.MatVecHadamardPar(input, workers)
+	if err != nil {
+		fmt.Printf("FWHT failed: %v\n", err)
+		return
+	}
+
+	a := append([]bn254.G1Affine(nil), input...)
+	start := time.Now()
+	if err := fwht.MatVecHadamardParInPlace(a, workers); err != nil {
+		fmt.Printf("MatVecHadamardParInPlace failed: %v\n", err)
+		return
+	}
+	fmt.Printf("MatVecHadamardParInPlace done in %s\n", time.Since(start))
+
+	// scratch 재사용: 두 번 연속 실행해도 같은 버퍼
+	scratch := make([]bn254.G1Jac, n)
+	b := append([]bn254.G1Affine(nil), input...)
+	for k := 0; k < 2; k++ {
+		copy(b, input)
+		start = time.Now()
+		if err := fwht.MatVecHadamardParInPlaceScratch(b, scratch, workers); err != nil {
+			fmt.Printf("MatVecHadamardParInPlaceScratch failed: %v\n", err)
+			return
+		}
+	}
+	fmt.Printf("MatVecHadamardParInPlaceScratch done in %s\n", time.Since(start))
+
+	for i := 0; i < n; i++ {
+		if !a[i].Equal(&want[i]) || !b[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : in-place mismatch at index %d\n", i)
+			return
+		}
+	}
+	fmt.Printf("Check passed ✅ : in-place variants == MatVecHadamardPar\n")
+}
+
+// checkCtx runs the *Ctx variants to completion (result == MatVecHadamardPar, progress
+// reaches log2(n)·n/2) and with a cancel issued from the progress callback in the
+// middle stage: the call must return context.Canceled and run no later stage.
+func checkCtx(g bn254.G1Affine, n, exp, workers int) {
+	input := make([]bn254.G1Affine, n)
+	for i := 0; i < n; i++ {
+		input[i].ScalarMultiplication(&g, big.NewInt(int64(i+1)))
+	}
+	want, err := fwht.MatVecHadamardPar(input, workers)
+	if err != nil {
+		fmt.Printf("FWHT failed: %v\n", err)
+		return
+	}
+	total := int64(exp) * int64(n/2)
+
+	// 끝까지 실행: 결과와 진행률 검증
+	var last int64 = -1
+	monotone := true
+	full := func(stage int, done int64) {
+		if done < last {
+			monotone = false
+		}
+		last = done
+	}
+	out, err := fwht.MatVecHadamardParCtx(context.Background(), input, workers, full)
+	if err != nil {
+		fmt.Printf("MatVecHadamardParCtx failed: %v\n", err)
+		return
+	}
+	if !monotone || last != total {
+		fmt.Printf("Check failed ❌ : MatVecHadamardParCtx progress ended at %d of %d (monotone=%v)\n", last, total, monotone)
+		return
+	}
+	last, monotone = -1, true
+	a := append([]bn254.G1Affine(nil), input...)
+	if err := fwht.MatVecHadamardSerialInPlaceCtx(context.Background(), a, full); err != nil {
+		fmt.Printf("MatVecHadamardSerialInPlaceCtx failed: %v\n", err)
+		return
+	}
+	if !monotone || last != total {
+		fmt.Printf("Check failed ❌ : MatVecHadamardSerialInPlaceCtx progress ended at %d of %d (monotone=%v)\n", last, total, monotone)
+		return
+	}
+	for i := 0; i < n; i++ {
+		if !out[i].Equal(&want[i]) || !a[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : Ctx variant mismatch at index %d\n", i)
+			return
+		}
+	}
+
+	// 중간 stage 에서 취소
+	if exp >= 2 {
+		cut := exp / 2
+		run := func(label string, call func(context.Context, fwht.ProgressFunc) error) bool {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			maxStage := -1
+			var done int64
+			err := call(ctx, func(stage int, d int64) {
+				maxStage, done = max(maxStage, stage), d
+				if stage == cut {
+					cancel()
+				}
+			})
+			if !errors.Is(err, context.Canceled) || maxStage != cut || done >= total {
+				fmt.Printf("Check failed ❌ : %s cancelled in stage %d: err=%v, last stage %d, done %d of %d\n", label, cut, err, maxStage, done, total)
+				return false
+			}
+			fmt.Printf("%s stopped in stage %d after %d of %d butterflies\n", label, cut, done, total)
+			return true
+		}
+		if !run("MatVecHadamardParCtx", func(ctx context.Context, fn fwht.ProgressFunc) error {
+			res, err := fwht.MatVecHadamardParCtx(ctx, input, workers, fn)
+			if res != nil {
+				return errors.New("result returned after cancel")
+			}
+			return err
+		}) {
+			return
+		}
+		b := append([]bn254.G1Affine(nil), input...)
+		if !run("MatVecHadamardSerialInPlaceCtx", func(ctx context.Context, fn fwht.ProgressFunc) error {
+			return fwht.MatVecHadamardSerialInPlaceCtx(ctx, b, fn)
+		}) {
+			return
+		}
+		for i := 0; i < n; i++ {
+			if !b[i].Equal(&input[i]) {
+				fmt.Printf("Check failed ❌ : cancelled MatVecHadamardSerialInPlaceCtx modified index %d\n", i)
+				return
+			}
+		}
+	}
+
+	// BatchJacToAffG1ParCtx: 정상 실행 == BatchJacToAffG1Par, 취소된 ctx 는 에러
+	jac := make([]bn254.G1Jac, n)
+	for i := range jac {
+		jac[i].FromAffine(&want[i])
+	}
+	aff, err := fwht.BatchJacToAffG1ParCtx(context.Background(), jac, workers)
+	if err != nil {
+		fmt.Printf("BatchJacToAffG1ParCtx failed: %v\n", err)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fwht.BatchJacToAffG1ParCtx(ctx, jac, workers); !errors.Is(err, context.Canceled) {
+		fmt.Printf("Check failed ❌ : BatchJacToAffG1ParCtx on a cancelled ctx returned %v\n", err)
+		return
+	}
+	for i := 0; i < n; i++ {
+		if !aff[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : BatchJacToAffG1ParCtx mismatch at index %d\n", i)
+			return
+		}
 	}
+	fmt.Printf("Check passed ✅ : Ctx variants == MatVecHadamardPar, progress reaches %d, cancel stops mid-run\n", total)
 }
@@ -0,0 +1,84 @@
+// go run ./cmd/fwhtupdate <exp> <workers> <k>
+//
+//	Applies k random (index, delta) changes to H·G with fwht.UpdateHadamardPar and
+//	checks the result against a full MatVecHadamardPar of the updated input.
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: go run ./cmd/fwhtupdate <exp> <workers> <k>")
+		fmt.Println("Example: go run ./cmd/fwhtupdate 12 4 20   # 20 changed points of a 2^12 vector")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	k, err := strconv.Atoi(os.Args[3])
+	if err != nil || k < 0 {
+		fmt.Printf("invalid k: %v\n", os.Args[3])
+		return
+	}
+
+	g, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	out, err := fwht.MatVecHadamardPar(g, workers)
+	must(err)
+
+	// k개의 변경: 같은 인덱스가 여러 번 나올 수 있음
+	d, err := randutil.RandomPointsG1Par(k, workers)
+	must(err)
+	deltas := make([]fwht.PointDelta, k)
+	updated := append([]bn254.G1Affine(nil), g...)
+	for t := range deltas {
+		deltas[t] = fwht.PointDelta{Index: rand.Intn(n), Delta: d[t]}
+		updated[deltas[t].Index].Add(&updated[deltas[t].Index], &d[t])
+	}
+
+	start := time.Now()
+	must(fwht.UpdateHadamardPar(out, deltas, workers))
+	tUpd := time.Since(start)
+
+	start = time.Now()
+	want, err := fwht.MatVecHadamardPar(updated, workers)
+	must(err)
+	tFull := time.Since(start)
+	fmt.Printf("UpdateHadamardPar (k=%d): %s | full MatVecHadamardPar: %s\n", k, tUpd, tFull)
+
+	for i := range want {
+		if !out[i].Equal(&want[i]) {
+			fmt.Printf("Check failed ❌ : mismatch at index %d\n", i)
+			return
+		}
+	}
+	fmt.Println("Check passed ✅ : UpdateHadamardPar == MatVecHadamardPar of the updated input")
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
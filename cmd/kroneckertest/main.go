@@ -0,0 +1,114 @@
+// go run ./cmd/kroneckertest <exp> <workers>
+//
+//	Checks fwht.KroneckerFrParInPlace against the dense K^{⊗exp} matrix and
+//	fwht.KroneckerPar against the scalar result: K·(s·G) == (K·s)·G.
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/kroneckertest <exp> <workers>")
+		fmt.Println("Example: go run ./cmd/kroneckertest 8 4   # n = 2^8")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp <= 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers, err := strconv.Atoi(os.Args[2])
+	if err != nil || workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	kernels := []struct {
+		name string
+		k    fwht.Kernel
+	}{
+		{"hadamard", fwht.HadamardKernel()},
+		{"[[1,0],[1,1]]", fwht.NewKernel(1, 0, 1, 1)},
+		{"[[1,1],[0,1]]", fwht.NewKernel(1, 1, 0, 1)},
+		{"[[2,3],[5,-7]]", fwht.NewKernel(2, 3, 5, -7)},
+	}
+
+	s, err := randutil.RandomScalarsPar(n, workers)
+	must(err)
+	_, _, g, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, n)
+	for i := range points {
+		points[i].ScalarMultiplication(&g, s[i].BigInt(new(big.Int)))
+	}
+
+	ok := true
+	for _, kc := range kernels {
+		ks := append([]fr.Element(nil), s...)
+		must(fwht.KroneckerFrParInPlace(ks, kc.k, workers))
+
+		// dense: (K^{⊗m})[i][j] = Π_b K[i_b][j_b]
+		if exp <= 10 {
+			kk := [2][2]fr.Element{}
+			kk[0][0].SetBigInt(kc.k.A)
+			kk[0][1].SetBigInt(kc.k.B)
+			kk[1][0].SetBigInt(kc.k.C)
+			kk[1][1].SetBigInt(kc.k.D)
+			for i := 0; ok && i < n; i++ {
+				var acc fr.Element
+				for j := 0; j < n; j++ {
+					var e fr.Element
+					e.SetOne()
+					for b := 0; b < exp; b++ {
+						e.Mul(&e, &kk[i>>b&1][j>>b&1])
+					}
+					e.Mul(&e, &s[j])
+					acc.Add(&acc, &e)
+				}
+				if !acc.Equal(&ks[i]) {
+					fmt.Printf("%s: scalar mismatch with dense matrix at index %d\n", kc.name, i)
+					ok = false
+				}
+			}
+		}
+
+		kp, err := fwht.KroneckerPar(points, kc.k, workers)
+		must(err)
+		for i := 0; ok && i < n; i++ {
+			var expect bn254.G1Affine
+			expect.ScalarMultiplication(&g, ks[i].BigInt(new(big.Int)))
+			if !kp[i].Equal(&expect) {
+				fmt.Printf("%s: K·(s·G) != (K·s)·G at index %d\n", kc.name, i)
+				ok = false
+			}
+		}
+		if ok {
+			fmt.Printf("kernel %s ok\n", kc.name)
+		}
+	}
+
+	if ok {
+		fmt.Println("Check passed ✅ : Kronecker transforms match dense K^{⊗m} and K·(s·G) == (K·s)·G")
+	} else {
+		fmt.Println("Check failed ❌")
+	}
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
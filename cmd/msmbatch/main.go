@@ -0,0 +1,100 @@
+// go run ./cmd/msmbatch <exp> <k> [workers] [impl] [window]
+//
+//	Computes k MSMs of random scalar vectors against one random base vector of
+//	size 2^exp with msm.MultiMSM and checks every result against MultiExpMSM.
+//	impl: "batchaffine" (default), "signed" or "pippenger" (unsigned digits)
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/msm"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: go run ./cmd/msmbatch <exp> <k> [workers] [impl] [window]")
+		fmt.Println("Example: go run ./cmd/msmbatch 12 32 4 batchaffine   # 32 MSMs of size 2^12")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	k, err := strconv.Atoi(os.Args[2])
+	if err != nil || k < 0 {
+		fmt.Printf("invalid k: %v\n", os.Args[2])
+		return
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(os.Args) >= 4 {
+		if w, err := strconv.Atoi(os.Args[3]); err == nil && w > 0 {
+			workers = w
+		}
+	}
+
+	impl := "batchaffine"
+	if len(os.Args) >= 5 {
+		impl = strings.ToLower(os.Args[4])
+	}
+	if impl != "signed" && impl != "pippenger" && impl != "batchaffine" {
+		fmt.Printf("invalid impl: %v\n", impl)
+		return
+	}
+	window := 0
+	if len(os.Args) >= 6 {
+		window, err = strconv.Atoi(os.Args[5])
+		must(err)
+	}
+	cfg := msm.PippengerConfig{Window: window, Signed: impl != "pippenger", BatchAffine: impl == "batchaffine", Workers: workers}
+
+	points, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	scalars := make([][]fr.Element, k)
+	for v := range scalars {
+		scalars[v], err = randutil.RandomScalarsPar(n, workers)
+		must(err)
+	}
+
+	start := time.Now()
+	got, err := msm.MultiMSM(points, scalars, cfg)
+	must(err)
+	tMulti := time.Since(start)
+
+	start = time.Now()
+	want := make([]bn254.G1Affine, k)
+	for v := range scalars {
+		want[v], err = msm.MultiExpMSM(points, scalars[v])
+		must(err)
+	}
+	tRepeat := time.Since(start)
+	fmt.Printf("MultiMSM (k=%d, n=%d, %s): %s | %d x MultiExpMSM: %s\n", k, n, impl, tMulti, k, tRepeat)
+
+	for v := range want {
+		if !got[v].Equal(&want[v]) {
+			fmt.Printf("Check failed ❌ : mismatch at vector %d\n", v)
+			return
+		}
+	}
+	fmt.Println("Check passed ✅ : MultiMSM == repeated MultiExpMSM")
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
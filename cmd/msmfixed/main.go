@@ -0,0 +1,158 @@
+// go run ./cmd/msmfixed <exp> [workers]
+//
+//	Builds msm.FixedBaseMSM tables for 2^exp random bases and checks, against
+//	PippengerMSM on random scalars: the in-memory tables, tables written with
+//	WriteFile and loaded with ReadFixedBaseMSM, and tables built under memory
+//	budgets that force larger strides (down to one copy of the bases). Also checks
+//	that ReadFixedBaseMSM rejects truncated and corrupted files.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/msm"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+// pointBytes is the size of one table point (X||Y); a budget below one copy of
+// the bases still gets that copy.
+const pointBytes = 64
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run ./cmd/msmfixed <exp> [workers]")
+		fmt.Println("Example: go run ./cmd/msmfixed 12 4   # 2^12 fixed bases")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(os.Args) >= 3 {
+		if w, err := strconv.Atoi(os.Args[2]); err == nil && w > 0 {
+			workers = w
+		}
+	}
+
+	points, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	scalars, err := randutil.RandomScalarsPar(n, workers)
+	must(err)
+	want, err := msm.PippengerMSM(points, scalars, msm.PippengerConfig{Signed: true, Workers: workers})
+	must(err)
+
+	// 메모리 제한 없음: window 마다 테이블 하나
+	start := time.Now()
+	f, err := msm.NewFixedBaseMSM(points, msm.FixedBaseConfig{Workers: workers})
+	must(err)
+	fmt.Printf("NewFixedBaseMSM (n=%d): c=%d, stride=%d, %d bytes in %s\n", n, f.Window(), f.Stride(), f.TableBytes(), time.Since(start))
+	if f.Stride() != 1 || !checkMSM("in-memory tables", f, scalars, want, workers) {
+		return
+	}
+
+	// 파일 왕복
+	dir, err := os.MkdirTemp("", "msmfixed")
+	must(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tables.bin")
+	must(f.WriteFile(path))
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		fmt.Println("Check failed ❌ : WriteFile left its .tmp file behind")
+		return
+	}
+	g, err := msm.ReadFixedBaseMSM(path)
+	must(err)
+	if g.Len() != f.Len() || g.Window() != f.Window() || g.Stride() != f.Stride() || g.TableBytes() != f.TableBytes() {
+		fmt.Printf("Check failed ❌ : read tables (n=%d, c=%d, stride=%d) differ from written ones\n", g.Len(), g.Window(), g.Stride())
+		return
+	}
+	if !checkMSM("ReadFixedBaseMSM(WriteFile)", g, scalars, want, workers) {
+		return
+	}
+
+	// 메모리 예산: 테이블 크기의 1/4 이하, 그리고 1 byte (= 기저 한 벌, plain signed Pippenger)
+	for _, budget := range []int64{f.TableBytes() / 4, 1} {
+		for _, window := range []int{0, f.Window()} {
+			h, err := msm.NewFixedBaseMSM(points, msm.FixedBaseConfig{Window: window, MemoryBudget: budget, Workers: workers})
+			must(err)
+			label := fmt.Sprintf("budget %d bytes, window %d (c=%d, stride=%d, %d bytes)", budget, window, h.Window(), h.Stride(), h.TableBytes())
+			if h.Stride() <= 1 || (h.TableBytes() > budget && h.TableBytes() != int64(n)*pointBytes) {
+				fmt.Printf("Check failed ❌ : %s does not respect the budget\n", label)
+				return
+			}
+			if !checkMSM(label, h, scalars, want, workers) {
+				return
+			}
+			fmt.Printf("%s ok\n", label)
+		}
+	}
+
+	// 잘린 파일 / 손상된 파일은 거부
+	raw, err := os.ReadFile(path)
+	must(err)
+	hdr := 8 + 24
+	corrupt := []struct {
+		label string
+		data  []byte
+	}{
+		{"truncated by one byte", raw[:len(raw)-1]},
+		{"header only", raw[:hdr]},
+		{"bad magic", flip(raw, 0, 0x01)},
+		{"n changed", flip(raw, 8+7, 0x01)},
+		{"non-canonical X", set(raw, hdr, 0xff)},
+		{"point off the curve", flip(raw, hdr+63, 0x01)},
+	}
+	for _, c := range corrupt {
+		bad := filepath.Join(dir, "bad.bin")
+		must(os.WriteFile(bad, c.data, 0o644))
+		if _, err := msm.ReadFixedBaseMSM(bad); err == nil {
+			fmt.Printf("Check failed ❌ : ReadFixedBaseMSM accepted a file with %s\n", c.label)
+			return
+		}
+	}
+	fmt.Println("Check passed ✅ : fixed-base MSM (in memory, read back, under budget) == PippengerMSM, bad files rejected")
+}
+
+// checkMSM compares f.MSM(scalars) with want.
+func checkMSM(label string, f *msm.FixedBaseMSM, scalars []fr.Element, want bn254.G1Affine, workers int) bool {
+	got, err := f.MSM(scalars, workers)
+	must(err)
+	if !got.Equal(&want) {
+		fmt.Printf("Check failed ❌ : %s: MSM != PippengerMSM\n", label)
+		return false
+	}
+	return true
+}
+
+// flip returns a copy of raw with raw[i] ^= x.
+func flip(raw []byte, i int, x byte) []byte {
+	out := append([]byte(nil), raw...)
+	out[i] ^= x
+	return out
+}
+
+// set returns a copy of raw with raw[i] = x.
+func set(raw []byte, i int, x byte) []byte {
+	out := append([]byte(nil), raw...)
+	out[i] = x
+	return out
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
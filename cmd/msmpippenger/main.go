@@ -0,0 +1,120 @@
+// go run ./cmd/msmpippenger <exp> [workers]
+//
+//	Checks msm.PippengerMSM against MultiExpMSM on 2^exp points for unsigned and
+//	signed digits, Jacobian and batch-affine buckets and several windows, on three
+//	inputs: random scalars (with 0, 1 and -1 mixed in), and one repeated point with
+//	scalars ±s, whose digits all land in the same buckets so that batch-affine
+//	additions take the Jacobian overflow, doubling and P + (-P) paths.
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/msm"
+	"github.com/Han-16/fwhtist/internal/randutil"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println("Usage: go run ./cmd/msmpippenger <exp> [workers]")
+		fmt.Println("Example: go run ./cmd/msmpippenger 10 4   # 2^10 points")
+		return
+	}
+
+	exp, err := strconv.Atoi(os.Args[1])
+	if err != nil || exp < 0 {
+		fmt.Printf("invalid exp: %v\n", os.Args[1])
+		return
+	}
+	n := 1 << exp
+
+	workers := runtime.GOMAXPROCS(0)
+	if len(os.Args) >= 3 {
+		if w, err := strconv.Atoi(os.Args[2]); err == nil && w > 0 {
+			workers = w
+		}
+	}
+
+	// 임의의 점과 스칼라 (0, 1, -1 포함)
+	points, err := randutil.RandomPointsG1Par(n, workers)
+	must(err)
+	scalars, err := randutil.RandomScalarsPar(n, workers)
+	must(err)
+	for i, v := range []int64{0, 1, -1} {
+		if i < n {
+			scalars[i].SetInt64(v)
+		}
+	}
+
+	// 같은 점, 스칼라 ±s: 모든 digit 이 같은 bucket 으로 (overflow, doubling, P + (-P))
+	same := make([]bn254.G1Affine, n)
+	for i := range same {
+		same[i] = points[0]
+	}
+	s, err := randutil.RandomScalarsPar(1, workers)
+	must(err)
+	pm := make([]fr.Element, n)
+	for i := range pm {
+		pm[i] = s[0]
+		if i%3 == 2 {
+			pm[i].Neg(&s[0])
+		}
+	}
+
+	inputs := []struct {
+		label   string
+		points  []bn254.G1Affine
+		scalars []fr.Element
+	}{
+		{"random", points, scalars},
+		{"same point, ±s", same, pm},
+	}
+	checks := 0
+	for _, in := range inputs {
+		want, err := msm.MultiExpMSM(in.points, in.scalars)
+		must(err)
+		for _, window := range []int{0, 1, 4, 8, 13, 16} {
+			for _, signed := range []bool{false, true} {
+				for _, batchAffine := range []bool{false, true} {
+					cfg := msm.PippengerConfig{Window: window, Signed: signed, BatchAffine: batchAffine, Workers: workers}
+					start := time.Now()
+					got, err := msm.PippengerMSM(in.points, in.scalars, cfg)
+					must(err)
+					if !got.Equal(&want) {
+						fmt.Printf("Check failed ❌ : %s: PippengerMSM (window %d, signed %v, batch-affine %v) != MultiExpMSM\n",
+							in.label, window, signed, batchAffine)
+						return
+					}
+					checks++
+					if window == 0 {
+						fmt.Printf("%s: PippengerMSM (signed %v, batch-affine %v): %s\n", in.label, signed, batchAffine, time.Since(start))
+					}
+				}
+			}
+		}
+	}
+
+	// 길이 불일치, 너무 큰 window 는 거부
+	if _, err := msm.PippengerMSM(points, scalars[:n/2], msm.PippengerConfig{}); err == nil {
+		fmt.Println("Check failed ❌ : PippengerMSM accepted mismatched lengths")
+		return
+	}
+	if _, err := msm.PippengerMSM(points, scalars, msm.PippengerConfig{Window: 64}); err == nil {
+		fmt.Println("Check failed ❌ : PippengerMSM accepted window 64")
+		return
+	}
+	fmt.Printf("Check passed ✅ : %d PippengerMSM configurations == MultiExpMSM, bad inputs rejected\n", checks)
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
@@ -4,11 +4,11 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
-	"math/bits"
 	"time"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
@@ -16,6 +16,9 @@ import (
 	swemu "github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
 	gnarkbits "github.com/consensys/gnark/std/math/bits"
 	emu "github.com/consensys/gnark/std/math/emulated"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+	"github.com/Han-16/fwhtist/internal/msm"
 )
 
 // Defines constants for the circuit.
@@ -26,6 +29,11 @@ const (
 	MatrixSize = 1 << NumBits
 	// Number of indices to select and process from the Hadamard transform result.
 	NumIndices = 18
+	// Row ordering the public indices refer to (natural, sequency or dyadic).
+	RowOrdering = fwht.OrderNatural
+	// Stages of the transform (bit s = along index bit s); other bits are identity.
+	// All stages is the full Hadamard transform, e.g. fwht.StageMask(0, 1, 2) the low 3 bits only.
+	RowStages uint64 = 1<<NumBits - 1
 )
 
 // Affine represents a point on the BN254 curve in affine coordinates.
@@ -45,6 +53,12 @@ type FWHTIndicesCircuit struct {
 	R [NumIndices]emu.Element[emu.BN254Fr] `gnark:",public"`
 	// The publicly known final aggregated result of the computation.
 	Agg Affine `gnark:",public"`
+
+	// --- Compile-time parameter ---
+	// Row ordering of Indices; mapped to natural Hadamard rows inside the circuit.
+	Ordering fwht.Ordering `gnark:"-"`
+	// Stage mask of the transform, as in fwht.MatVecHadamardStagesPar.
+	Stages uint64 `gnark:"-"`
 }
 
 // must is a helper function to panic on error.
@@ -56,7 +70,9 @@ func must(err error) {
 
 // computeFWHTRow computes the product of a specific row of the Hadamard matrix
 // and the vector G using the Fast Walsh-Hadamard Transform (FWHT) algorithm inside the circuit.
-func computeFWHTRow(curve *swemu.Curve[emu.BN254Fp, emu.BN254Fr], g []Affine, indexBits []frontend.Variable) *Affine {
+// Stages outside the mask are the identity: the fold then keeps p1 or p2 by the
+// index bit instead of p1 ± p2, matching fwht.MatVecHadamardStagesPar.
+func computeFWHTRow(curve *swemu.Curve[emu.BN254Fp, emu.BN254Fr], g []Affine, indexBits []frontend.Variable, stages uint64) *Affine {
 	// Slice to store the results of the current computation stage.
 	currentStageG := make([]*Affine, len(g))
 	for i := range g {
@@ -76,6 +92,12 @@ func computeFWHTRow(curve *swemu.Curve[emu.BN254Fp, emu.BN254Fr], g []Affine, in
 			p1 := currentStageG[2*i]
 			p2 := currentStageG[2*i+1]
 
+			if stages&(1<<uint(s)) == 0 {
+				// Identity along bit s: select the entry whose bit s equals the index bit.
+				nextStageG[i] = curve.Select(indexBits[s], p2, p1)
+				continue
+			}
+
 			// Pre-compute (p1 + p2) and (p1 - p2).
 			termAdd := curve.AddUnified(p1, p2)
 			termSub := curve.AddUnified(p1, curve.Neg(p2))
@@ -92,6 +114,29 @@ func computeFWHTRow(curve *swemu.Curve[emu.BN254Fp, emu.BN254Fr], g []Affine, in
 	return currentStageG[0]
 }
 
+// naturalRowBits maps the LSB-first bits of an ordered row index k to the bits of
+// its natural row fwht.RowToNatural(ordering, k, NumBits).
+// dyadic: bit-reversal only. sequency: Gray code g_i = k_i XOR k_{i+1}, then bit-reversal.
+func naturalRowBits(api frontend.API, kBits []frontend.Variable, ordering fwht.Ordering) []frontend.Variable {
+	m := len(kBits)
+	src := kBits
+	switch ordering {
+	case fwht.OrderNatural:
+		return kBits
+	case fwht.OrderSequency:
+		src = make([]frontend.Variable, m)
+		for i := 0; i < m-1; i++ {
+			src[i] = api.Xor(kBits[i], kBits[i+1])
+		}
+		src[m-1] = kBits[m-1]
+	}
+	out := make([]frontend.Variable, m)
+	for i := 0; i < m; i++ {
+		out[i] = src[m-1-i]
+	}
+	return out
+}
+
 // Define defines the logic of the circuit.
 func (c *FWHTIndicesCircuit) Define(api frontend.API) error {
 	curve, err := swemu.New[emu.BN254Fp, emu.BN254Fr](api, swemu.GetBN254Params())
@@ -104,9 +149,10 @@ func (c *FWHTIndicesCircuit) Define(api frontend.API) error {
 	for i := 0; i < NumIndices; i++ {
 		// Convert the integer index to its binary representation (10 bits).
 		idxBits := gnarkbits.ToBinary(api, c.Indices[i], gnarkbits.WithNbDigits(NumBits))
+		idxBits = naturalRowBits(api, idxBits, c.Ordering)
 
 		// Call the helper function to compute the FWHT result for the current index.
-		Ys[i] = computeFWHTRow(curve, c.G[:], idxBits)
+		Ys[i] = computeFWHTRow(curve, c.G[:], idxBits, c.Stages)
 	}
 
 	// Final aggregation: R[0]*Y[0] + R[1]*Y[1] + ... + R[17]*Y[17].
@@ -125,40 +171,11 @@ func (c *FWHTIndicesCircuit) Define(api frontend.API) error {
 	return nil
 }
 
-// hadamardTransformRow computes the standard Hadamard transform for a single row
-// outside the circuit. This is used to generate the witness (the expected result).
-func hadamardTransformRow(g []bn254.G1Affine, rowIndex int) bn254.G1Affine {
-	var result bn254.G1Affine
-
-	// The value of H[i][j] is (-1)^(<i,j>), where <i,j> is the bitwise dot product
-	// of the binary representations of i and j. This is equivalent to popcount(i & j) % 2.
-	for j := 0; j < MatrixSize; j++ {
-		// Count the number of set bits (popcount) after a bitwise AND between i and j.
-		popcount := bits.OnesCount(uint(rowIndex & j))
-
-		// Add or subtract the point g[j] based on the popcount.
-		if j == 0 {
-			if popcount%2 == 1 {
-				result.Neg(&g[j])
-			} else {
-				result = g[j]
-			}
-		} else {
-			if popcount%2 == 1 {
-				result.Add(&result, new(bn254.G1Affine).Neg(&g[j]))
-			} else {
-				result.Add(&result, &g[j])
-			}
-		}
-	}
-	return result
-}
-
 func main() {
 	field := ecc.BN254.ScalarField()
 
 	// 1. Compile the circuit.
-	var circuit FWHTIndicesCircuit
+	circuit := FWHTIndicesCircuit{Ordering: RowOrdering, Stages: RowStages}
 	fmt.Println("Compiling circuit...")
 	t0 := time.Now()
 	cs, err := frontend.Compile(field, r1cs.NewBuilder, &circuit)
@@ -202,25 +219,16 @@ func main() {
 
 	// 3. Pre-compute the final result (Agg) for the Public Witness.
 	fmt.Println("Calculating expected Agg value...")
-	var aggCalculator bn254.G1Affine
-	var aggInitialized bool
 	t3 := time.Now()
+	rowIndices := make([]int, NumIndices)
+	rFr := make([]fr.Element, NumIndices)
 	for i := 0; i < NumIndices; i++ {
-		rowIndex := indices[i].(int)
-		// Y_i = H[rowIndex] * G
-		y := hadamardTransformRow(g[:], rowIndex)
-
-		// term = R_i * Y_i
-		var term bn254.G1Affine
-		term.ScalarMultiplication(&y, rBig[i])
-
-		if !aggInitialized {
-			aggCalculator = term
-			aggInitialized = true
-		} else {
-			aggCalculator.Add(&aggCalculator, &term)
-		}
+		rowIndices[i] = fwht.RowToNatural(RowOrdering, indices[i].(int), NumBits)
+		rFr[i].SetBigInt(rBig[i])
 	}
+	// Agg = Σ R_i * (H[rowIndex_i] * G) = (Σ R_i * H[rowIndex_i]) * G, one MSM
+	aggCalculator, err := msm.HadamardRowsRLCStages(g[:], rowIndices, rFr, RowStages)
+	must(err)
 	fmt.Println("Agg value calculated in:", time.Since(t3))
 
 	// 4. Assign the witness.
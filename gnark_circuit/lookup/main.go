@@ -2,12 +2,16 @@ package main
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark/backend/groth16"
 	"github.com/consensys/gnark/frontend"
 	"github.com/consensys/gnark/frontend/cs/r1cs"
 	"github.com/consensys/gnark/std/math/bits"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
 )
 
 // Hadamard matrix (8x8)
@@ -115,10 +119,26 @@ func main() {
 	// {1, -1, 1, -1, -1, 1, -1, 1}
 	// dot = 1-2+3-4-5+6-7+8 = 0
 
+	// Result is computed natively as (H·X)[Indices] with the scalar FWHT.
+	indices := [3]int{1, 2, 5}
+	var x [8]frontend.Variable
+	hx := make([]fr.Element, 8)
+	for j := range hx {
+		x[j] = j + 1
+		hx[j].SetUint64(uint64(j + 1))
+	}
+	must(fwht.MatVecHadamardFrSerialInPlace(hx))
+
+	var indexVars, result [3]frontend.Variable
+	for i, idx := range indices {
+		indexVars[i] = idx
+		result[i] = hx[idx].BigInt(new(big.Int))
+	}
+
 	assignment := &DotProductLookupCircuit{
-		Indices: [3]frontend.Variable{1, 2, 5},
-		X:       [8]frontend.Variable{1, 2, 3, 4, 5, 6, 7, 8},
-		Result:  [3]frontend.Variable{-4, -8, 0},
+		Indices: indexVars,
+		X:       x,
+		Result:  result,
 	}
 
 	fullWitness, err := frontend.NewWitness(assignment, field)
@@ -0,0 +1,107 @@
+package fwht
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// MatVecHadamardBatchPar transforms every vector of vs independently (lengths may
+// differ, each a power of two). Vectors holding at most a worker's share of all
+// points are spread across workers one vector per goroutine; larger ones are
+// transformed one at a time with all workers. All outputs share one backing array
+// and a single batch inversion (BatchJacToAffG1Par).
+func MatVecHadamardBatchPar(vs [][]bn254.G1Affine, workers int) ([][]bn254.G1Affine, error) {
+	offs := make([]int, len(vs)+1)
+	for k, v := range vs {
+		if len(v) != 0 && !isPowerOfTwo(len(v)) {
+			return nil, fmt.Errorf("MatVecHadamardBatchPar: vector %d: length must be a power of two", k)
+		}
+		offs[k+1] = offs[k] + len(v)
+	}
+	workers = normWorkers(workers)
+
+	buf := make([]bn254.G1Jac, offs[len(vs)])
+	share := len(buf) / workers
+	var small []int
+	for k, v := range vs {
+		if len(v) <= share {
+			small = append(small, k)
+			continue
+		}
+		row := buf[offs[k]:offs[k+1]]
+		parallelRange(len(v), workers, func(i0, i1 int) {
+			for i := i0; i < i1; i++ {
+				row[i].FromAffine(&v[i])
+			}
+		})
+	}
+	parallelTasks(len(small), workers, func(t int) {
+		k := small[t]
+		row := buf[offs[k]:offs[k+1]]
+		for i := range row {
+			row[i].FromAffine(&vs[k][i])
+		}
+	})
+	flat := batchStagesToAffine(buf, offs, workers)
+
+	out := make([][]bn254.G1Affine, len(vs))
+	for k := range vs {
+		out[k] = flat[offs[k]:offs[k+1]:offs[k+1]]
+	}
+	return out, nil
+}
+
+// MatVecHadamardStridedPar overwrites each row m[r·stride : r·stride+n] of a flat
+// matrix (len(m)/stride rows; len(m) a multiple of stride >= n) with its transform.
+// Scheduling and conversion are as in MatVecHadamardBatchPar.
+func MatVecHadamardStridedPar(m []bn254.G1Affine, n, stride, workers int) error {
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return errors.New("MatVecHadamardStridedPar: length must be a power of two")
+	}
+	if stride < n || len(m)%stride != 0 {
+		return errors.New("MatVecHadamardStridedPar: need stride >= n and len(m) a multiple of stride")
+	}
+	rows := len(m) / stride
+	workers = normWorkers(workers)
+
+	offs := make([]int, rows+1)
+	for r := 0; r < rows; r++ {
+		offs[r+1] = offs[r] + n
+	}
+	buf := make([]bn254.G1Jac, rows*n)
+	parallelRange(rows*n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&m[i/n*stride+i%n])
+		}
+	})
+	flat := batchStagesToAffine(buf, offs, workers)
+	parallelTasks(rows, workers, func(r int) {
+		copy(m[r*stride:r*stride+n], flat[offs[r]:offs[r+1]])
+	})
+	return nil
+}
+
+// batchStagesToAffine transforms every segment buf[offs[k]:offs[k+1]] and
+// converts the whole buffer back to affine with one inversion.
+func batchStagesToAffine(buf []bn254.G1Jac, offs []int, workers int) []bn254.G1Affine {
+	share := len(buf) / workers
+	var small []int
+	for k := 0; k+1 < len(offs); k++ {
+		seg := buf[offs[k]:offs[k+1]]
+		if len(seg) > share {
+			hadamardStagesPar(seg, workers)
+		} else if len(seg) > 1 {
+			small = append(small, k)
+		}
+	}
+	parallelTasks(len(small), workers, func(t int) {
+		k := small[t]
+		hadamardStagesSerial(buf[offs[k]:offs[k+1]])
+	})
+	return BatchJacToAffG1Par(buf, workers)
+}
@@ -0,0 +1,162 @@
+package fwht
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// ctxCheckInterval is how many points/butterflies a serial loop processes
+// between ctx.Err() checks.
+const ctxCheckInterval = 1 << 12
+
+// ProgressFunc reports progress of a *Ctx transform: stage is the butterfly stage
+// index r (distance 1<<r) and done the butterflies completed so far over all
+// stages (total log2(n)·n/2). Calls are serialized and done is non-decreasing.
+type ProgressFunc func(stage int, done int64)
+
+// progress serializes ProgressFunc calls from concurrent tiles; nil fn is a no-op.
+type progress struct {
+	mu   sync.Mutex
+	fn   ProgressFunc
+	done int64
+}
+
+func (p *progress) add(stage int, k int64) {
+	if p.fn == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done += k
+	p.fn(stage, p.done)
+	p.mu.Unlock()
+}
+
+// MatVecHadamardParCtx is MatVecHadamardPar that stops with ctx.Err() if ctx is
+// cancelled (checked before every stage and tile) and reports per-tile progress
+// to fn (may be nil).
+func MatVecHadamardParCtx(ctx context.Context, in []bn254.G1Affine, workers int, fn ProgressFunc) ([]bn254.G1Affine, error) {
+	return matVecHadamardParCtx[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardParCtx", ctx, in, workers, fn)
+}
+
+// MatVecHadamardSerialInPlaceCtx is MatVecHadamardSerialInPlace with cancellation
+// (checked every ctxCheckInterval butterflies) and progress reporting.
+// On cancellation in is left unchanged.
+func MatVecHadamardSerialInPlaceCtx(ctx context.Context, in []bn254.G1Affine, fn ProgressFunc) error {
+	return matVecHadamardSerialInPlaceCtx[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardSerialInPlaceCtx", ctx, in, fn)
+}
+
+// GenericMatVecHadamardParCtx is MatVecHadamardParCtx over any short-Weierstrass group.
+func GenericMatVecHadamardParCtx[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](ctx context.Context, in []A, workers int, fn ProgressFunc) ([]A, error) {
+	return matVecHadamardParCtx[A, J, PA, PJ]("GenericMatVecHadamardParCtx", ctx, in, workers, fn)
+}
+
+// GenericMatVecHadamardSerialInPlaceCtx is MatVecHadamardSerialInPlaceCtx over any
+// short-Weierstrass group.
+func GenericMatVecHadamardSerialInPlaceCtx[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](ctx context.Context, in []A, fn ProgressFunc) error {
+	return matVecHadamardSerialInPlaceCtx[A, J, PA, PJ]("GenericMatVecHadamardSerialInPlaceCtx", ctx, in, fn)
+}
+
+func matVecHadamardParCtx[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, ctx context.Context, in []A, workers int, fn ProgressFunc) ([]A, error) {
+	n := len(in)
+	if n == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	workers = normWorkers(workers)
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+
+	if err := hadamardStagesParCtx[J, PJ](ctx, buf, workers, &progress{fn: fn}); err != nil {
+		return nil, err
+	}
+
+	out := make([]A, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[i])
+		}
+	})
+	return out, nil
+}
+
+func matVecHadamardSerialInPlaceCtx[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, ctx context.Context, in []A, fn ProgressFunc) error {
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	buf := make([]J, n)
+	for i := 0; i < n; i++ {
+		PJ(&buf[i]).FromAffine(&in[i])
+	}
+
+	// same (r, k) indexing as hadamardStagesSerial, with periodic checks
+	p := &progress{fn: fn}
+	stages := bits.Len(uint(n)) - 1
+	half := n >> 1
+	for r := 0; r < stages; r++ {
+		mask := (1 << r) - 1
+		dist := 1 << r
+		for k0 := 0; k0 < half; k0 += ctxCheckInterval {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			k1 := min(k0+ctxCheckInterval, half)
+			for k := k0; k < k1; k++ {
+				aIdx := ((k >> r) << (r + 1)) | (k & mask)
+				butterfly[J, PJ](&buf[aIdx], &buf[aIdx+dist])
+			}
+			p.add(r, int64(k1-k0))
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		PA(&in[i]).FromJacobian(&buf[i])
+	}
+	return nil
+}
+
+// hadamardStagesParCtx is hadamardStagesPar with a ctx check before every stage
+// and tile; once ctx is cancelled the remaining tiles are skipped.
+func hadamardStagesParCtx[J any, PJ JacAdder[J]](ctx context.Context, buf []J, workers int, p *progress) error {
+	n := len(buf)
+	for r, step := 0, 1; step < n; r, step = r+1, step<<1 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		block := step << 1
+		runStage(n, step, workers, func(t stageTask) {
+			if ctx.Err() != nil {
+				return
+			}
+			for b := t.b0; b < t.b1; b++ {
+				base := b * block
+				for j := t.j0; j < t.j1; j++ {
+					butterfly[J, PJ](&buf[base+j], &buf[base+j+step])
+				}
+			}
+			p.add(r, int64(t.b1-t.b0)*int64(t.j1-t.j0))
+		})
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,40 @@
+package fwht
+
+import (
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+)
+
+// Per-curve G1 instantiations of the generic transforms.
+// BN254 keeps the unsuffixed names (MatVecHadamardPar, MatVecHadamardSerialInPlace).
+
+// MatVecHadamardParBLS12381 is MatVecHadamardPar over BLS12-381 G1.
+func MatVecHadamardParBLS12381(in []bls12381.G1Affine, workers int) ([]bls12381.G1Affine, error) {
+	return matVecHadamardPar[bls12381.G1Affine, bls12381.G1Jac]("MatVecHadamardParBLS12381", in, workers)
+}
+
+// MatVecHadamardSerialInPlaceBLS12381 is MatVecHadamardSerialInPlace over BLS12-381 G1.
+func MatVecHadamardSerialInPlaceBLS12381(in []bls12381.G1Affine) error {
+	return matVecHadamardSerialInPlace[bls12381.G1Affine, bls12381.G1Jac]("MatVecHadamardSerialInPlaceBLS12381", in)
+}
+
+// MatVecHadamardParBLS12377 is MatVecHadamardPar over BLS12-377 G1.
+func MatVecHadamardParBLS12377(in []bls12377.G1Affine, workers int) ([]bls12377.G1Affine, error) {
+	return matVecHadamardPar[bls12377.G1Affine, bls12377.G1Jac]("MatVecHadamardParBLS12377", in, workers)
+}
+
+// MatVecHadamardSerialInPlaceBLS12377 is MatVecHadamardSerialInPlace over BLS12-377 G1.
+func MatVecHadamardSerialInPlaceBLS12377(in []bls12377.G1Affine) error {
+	return matVecHadamardSerialInPlace[bls12377.G1Affine, bls12377.G1Jac]("MatVecHadamardSerialInPlaceBLS12377", in)
+}
+
+// MatVecHadamardParBW6761 is MatVecHadamardPar over BW6-761 G1.
+func MatVecHadamardParBW6761(in []bw6761.G1Affine, workers int) ([]bw6761.G1Affine, error) {
+	return matVecHadamardPar[bw6761.G1Affine, bw6761.G1Jac]("MatVecHadamardParBW6761", in, workers)
+}
+
+// MatVecHadamardSerialInPlaceBW6761 is MatVecHadamardSerialInPlace over BW6-761 G1.
+func MatVecHadamardSerialInPlaceBW6761(in []bw6761.G1Affine) error {
+	return matVecHadamardSerialInPlace[bw6761.G1Affine, bw6761.G1Jac]("MatVecHadamardSerialInPlaceBW6761", in)
+}
@@ -0,0 +1,478 @@
+package fwht
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// Distributed FWHT over procs processes (power of two), each owning the contiguous
+// slice [rank·m, (rank+1)·m), m = n/procs:
+//   - the log2(m) low stages only pair points inside a slice and run locally;
+//   - global stage log2(m)+k pairs slice rank with slice rank^(1<<k) elementwise,
+//     so the two workers swap slices and each keeps one half of the butterfly.
+//
+// Wire format (big-endian integers, points as in the point file format):
+//
+//	worker -> coordinator: hello  = network, address of the worker's peer listener
+//	coordinator -> worker: assign = rank, procs, m, procs × (network, address), m affine points
+//	worker <-> worker:     rank once per connection, then m Jacobian points per stage
+//	worker -> coordinator: result = m affine points
+//
+// Every connection and the listeners get the deadline of ctx, and cancelling ctx
+// interrupts any pending Accept, Read or Write, so a lost peer ends the run with
+// ctx.Err() instead of blocking it. Listeners must support SetDeadline
+// (*net.TCPListener and *net.UnixListener do); the deadline is cleared on return.
+const jacWireSize = 3 * fp.Bytes
+
+// MatVecHadamardDist is the coordinator: it accepts procs workers (RunDistWorker)
+// on ln, hands out the slices of in, and gathers H·in. ln is not closed.
+func MatVecHadamardDist(ctx context.Context, ln net.Listener, in []bn254.G1Affine, procs int) (out []bn254.G1Affine, err error) {
+	const name = "MatVecHadamardDist"
+	n := len(in)
+	if n == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	if !isPowerOfTwo(procs) || procs > n {
+		return nil, fmt.Errorf("%s: procs must be a power of two <= n", name)
+	}
+	m := n / procs
+	watch, err := newDistWatch(ctx, ln)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() {
+		if watch.stop() && err != nil {
+			err = fmt.Errorf("%s: %w", name, ctx.Err())
+		}
+	}()
+
+	conns := make([]net.Conn, procs)
+	defer func() {
+		for _, c := range conns {
+			if c != nil {
+				c.Close()
+			}
+		}
+	}()
+	peers := make([]distAddr, procs)
+	for r := 0; r < procs; r++ {
+		c, err := ln.Accept()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		conns[r] = c
+		watch.add(c)
+		if peers[r], err = readDistAddr(bufio.NewReader(c)); err != nil {
+			return nil, fmt.Errorf("%s: hello from worker %d: %w", name, r, err)
+		}
+	}
+
+	out = make([]bn254.G1Affine, n)
+	errs := make([]error, procs)
+	parallelTasks(procs, procs, func(r int) {
+		errs[r] = runDistRank(conns[r], r, procs, peers, in[r*m:(r+1)*m], out[r*m:(r+1)*m])
+	})
+	for r, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: worker %d: %w", name, r, err)
+		}
+	}
+	return out, nil
+}
+
+// runDistRank sends rank r its assignment and reads its result into out.
+func runDistRank(c net.Conn, r, procs int, peers []distAddr, in, out []bn254.G1Affine) error {
+	w := bufio.NewWriter(c)
+	writeUint64(w, uint64(r))
+	writeUint64(w, uint64(procs))
+	writeUint64(w, uint64(len(in)))
+	for _, p := range peers {
+		writeDistAddr(w, p)
+	}
+	raw := make([]byte, len(in)*pointFileSize)
+	encodePoints(raw, in, 0)
+	w.Write(raw)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(c, raw); err != nil {
+		return err
+	}
+	return decodePoints(out, raw, 0)
+}
+
+// DistWorkerOptions configures RunDistWorker.
+type DistWorkerOptions struct {
+	Workers int // <= 0 => GOMAXPROCS(0)
+	// Advertise is the address partners dial to reach ln, on ln's network; "" =>
+	// ln.Addr(). Set it when ln is bound to a wildcard or NATed address.
+	Advertise string
+}
+
+// RunDistWorker serves one rank of MatVecHadamardDist: it registers ln (the
+// listener its butterfly partners dial) with the coordinator at coordAddr, runs
+// the local and exchange stages with opts.Workers goroutines, and returns once
+// its slice has been sent back. ln is not closed.
+func RunDistWorker(ctx context.Context, network, coordAddr string, ln net.Listener, opts DistWorkerOptions) (err error) {
+	const name = "RunDistWorker"
+	workers := normWorkers(opts.Workers)
+	watch, err := newDistWatch(ctx, ln)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer func() {
+		if watch.stop() && err != nil {
+			err = fmt.Errorf("%s: %w", name, ctx.Err())
+		}
+	}()
+
+	var d net.Dialer
+	c, err := d.DialContext(ctx, network, coordAddr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	defer c.Close()
+	watch.add(c)
+	self := distAddr{ln.Addr().Network(), ln.Addr().String()}
+	if opts.Advertise != "" {
+		self.addr = opts.Advertise
+	}
+	cw := bufio.NewWriter(c)
+	writeDistAddr(cw, self)
+	if err := cw.Flush(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	// assignment
+	cr := bufio.NewReader(c)
+	var hdr [3]uint64
+	for i := range hdr {
+		if hdr[i], err = readUint64(cr); err != nil {
+			return fmt.Errorf("%s: assignment: %w", name, err)
+		}
+	}
+	rank, procs, m := int(hdr[0]), int(hdr[1]), int(hdr[2])
+	if !isPowerOfTwo(procs) || rank >= procs || !isPowerOfTwo(m) {
+		return fmt.Errorf("%s: bad assignment rank=%d procs=%d m=%d", name, rank, procs, m)
+	}
+	peers := make([]distAddr, procs)
+	for i := range peers {
+		if peers[i], err = readDistAddr(cr); err != nil {
+			return fmt.Errorf("%s: assignment: %w", name, err)
+		}
+	}
+	raw := make([]byte, m*pointFileSize)
+	if _, err := io.ReadFull(cr, raw); err != nil {
+		return fmt.Errorf("%s: assignment: %w", name, err)
+	}
+	aff := make([]bn254.G1Affine, m)
+	if err := decodePoints(aff, raw, workers); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	partners, err := dialDistPartners(ctx, watch, ln, rank, procs, peers)
+	defer func() {
+		for _, p := range partners {
+			if p != nil {
+				p.Close()
+			}
+		}
+	}()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	// low stages: local
+	buf := make([]bn254.G1Jac, m)
+	parallelRange(m, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&aff[i])
+		}
+	})
+	hadamardStagesPar(buf, workers)
+
+	// high stages: swap slices with rank^(1<<k), lower rank keeps a+c, upper a-c
+	peer := make([]bn254.G1Jac, m)
+	for k := range partners {
+		if err := exchangeJac(partners[k], buf, peer, workers); err != nil {
+			return fmt.Errorf("%s: stage %d: %w", name, bits.Len(uint(m))-1+k, err)
+		}
+		lower := rank&(1<<k) == 0
+		parallelRange(m, workers, func(i0, i1 int) {
+			for i := i0; i < i1; i++ {
+				if lower {
+					butterfly(&buf[i], &peer[i])
+				} else {
+					butterfly(&peer[i], &buf[i])
+				}
+			}
+		})
+	}
+
+	encodePoints(raw, BatchJacToAffG1Par(buf, workers), workers)
+	if _, err := c.Write(raw); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// dialDistPartners connects rank to rank^(1<<k) for every k: the lower rank dials,
+// the upper accepts on ln. partners[k] is the connection for stage k. On error
+// the accept loop is stopped and every connection opened so far is closed.
+func dialDistPartners(ctx context.Context, watch *distWatch, ln net.Listener, rank, procs int, peers []distAddr) (partners []net.Conn, err error) {
+	logP := bits.Len(uint(procs)) - 1
+	partners = make([]net.Conn, logP)
+
+	inbound := bits.OnesCount(uint(rank))
+	accepted := make(chan error, 1)
+	acceptedConns := make([]net.Conn, 0, inbound)
+	go func() {
+		for i := 0; i < inbound; i++ {
+			c, err := ln.Accept()
+			if err != nil {
+				accepted <- err
+				return
+			}
+			watch.add(c)
+			acceptedConns = append(acceptedConns, c)
+		}
+		accepted <- nil
+	}()
+	defer func() {
+		if err == nil {
+			return
+		}
+		for k, c := range partners {
+			if c != nil {
+				c.Close()
+				partners[k] = nil
+			}
+		}
+		for _, c := range acceptedConns {
+			c.Close()
+		}
+	}()
+
+	var dialErr error
+	var d net.Dialer
+	for k := 0; k < logP && dialErr == nil; k++ {
+		if rank&(1<<k) != 0 {
+			continue
+		}
+		p := peers[rank^(1<<k)]
+		c, err := d.DialContext(ctx, p.network, p.addr)
+		if err != nil {
+			dialErr = err
+			break
+		}
+		partners[k] = c
+		watch.add(c)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], uint64(rank))
+		_, dialErr = c.Write(b[:])
+	}
+	if dialErr != nil {
+		// unblock the accept loop and wait for it before closing what it accepted
+		ln.(deadliner).SetDeadline(distPast)
+		<-accepted
+		return nil, dialErr
+	}
+	if err := <-accepted; err != nil {
+		return nil, err
+	}
+
+	for _, c := range acceptedConns {
+		var b [8]byte
+		if _, err := io.ReadFull(c, b[:]); err != nil {
+			return nil, err
+		}
+		from := int(binary.BigEndian.Uint64(b[:]))
+		k := bits.TrailingZeros(uint(from ^ rank))
+		if from >= procs || from^rank != 1<<k || partners[k] != nil {
+			return nil, fmt.Errorf("unexpected partner rank %d", from)
+		}
+		partners[k] = c
+	}
+	return partners, nil
+}
+
+// exchangeJac sends mine to c and reads the partner's slice into theirs concurrently
+// (both sides write m points before reading, which would fill the socket buffers).
+// Non-canonical coordinates from the partner are rejected, as in decodePoints.
+func exchangeJac(c net.Conn, mine, theirs []bn254.G1Jac, workers int) error {
+	out := make([]byte, len(mine)*jacWireSize)
+	parallelRange(len(mine), workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			r := out[i*jacWireSize:]
+			fp.BigEndian.PutElement((*[fp.Bytes]byte)(r[:fp.Bytes]), mine[i].X)
+			fp.BigEndian.PutElement((*[fp.Bytes]byte)(r[fp.Bytes:2*fp.Bytes]), mine[i].Y)
+			fp.BigEndian.PutElement((*[fp.Bytes]byte)(r[2*fp.Bytes:jacWireSize]), mine[i].Z)
+		}
+	})
+	sent := make(chan error, 1)
+	go func() {
+		_, err := c.Write(out)
+		sent <- err
+	}()
+
+	in := make([]byte, len(theirs)*jacWireSize)
+	_, err := io.ReadFull(c, in)
+	if serr := <-sent; err == nil {
+		err = serr
+	}
+	if err != nil {
+		return err
+	}
+	var bad atomic.Int64 // first bad index + 1
+	parallelRange(len(theirs), workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			r := in[i*jacWireSize:]
+			err := theirs[i].X.SetBytesCanonical(r[:fp.Bytes])
+			if err == nil {
+				err = theirs[i].Y.SetBytesCanonical(r[fp.Bytes : 2*fp.Bytes])
+			}
+			if err == nil {
+				err = theirs[i].Z.SetBytesCanonical(r[2*fp.Bytes : jacWireSize])
+			}
+			if err != nil {
+				bad.CompareAndSwap(0, int64(i)+1)
+				return
+			}
+		}
+	})
+	if i := bad.Load(); i != 0 {
+		return fmt.Errorf("peer point %d: non-canonical coordinate", i-1)
+	}
+	return nil
+}
+
+// deadliner is the deadline control shared by net.Conn, *net.TCPListener and
+// *net.UnixListener.
+type deadliner interface{ SetDeadline(t time.Time) error }
+
+// distPast is a deadline that makes pending and future I/O fail at once.
+var distPast = time.Unix(1, 0)
+
+// distWatch applies the deadline of ctx to every registered conn and listener
+// and, once ctx is done, sets a deadline in the past to interrupt pending I/O.
+type distWatch struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	ds        []deadliner
+	listeners []deadliner
+	done      bool
+	after     func() bool
+}
+
+// newDistWatch returns a watch with the listeners registered; they must support
+// SetDeadline.
+func newDistWatch(ctx context.Context, lns ...net.Listener) (*distWatch, error) {
+	w := &distWatch{ctx: ctx}
+	for _, ln := range lns {
+		d, ok := ln.(deadliner)
+		if !ok {
+			return nil, fmt.Errorf("listener %T does not support SetDeadline", ln)
+		}
+		w.listeners = append(w.listeners, d)
+		w.add(d)
+	}
+	w.after = context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.done = true
+		for _, d := range w.ds {
+			d.SetDeadline(distPast)
+		}
+	})
+	return w, nil
+}
+
+// add puts d under the watch.
+func (w *distWatch) add(d deadliner) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	switch dl, ok := w.ctx.Deadline(); {
+	case w.done:
+		d.SetDeadline(distPast)
+	case ok:
+		d.SetDeadline(dl)
+	}
+	w.ds = append(w.ds, d)
+}
+
+// stop detaches the watch, clears the listeners' deadlines and reports whether
+// ctx was done (so errors should be reported as ctx.Err()).
+func (w *distWatch) stop() bool {
+	w.after()
+	for _, d := range w.listeners {
+		d.SetDeadline(time.Time{})
+	}
+	return w.ctx.Err() != nil
+}
+
+// distAddr is a worker's peer listener address.
+type distAddr struct{ network, addr string }
+
+func writeDistAddr(w *bufio.Writer, a distAddr) {
+	writeString(w, a.network)
+	writeString(w, a.addr)
+}
+
+func readDistAddr(r *bufio.Reader) (a distAddr, err error) {
+	if a.network, err = readString(r); err == nil {
+		a.addr, err = readString(r)
+	}
+	return a, err
+}
+
+// distMaxString bounds length-prefixed strings read from the wire.
+const distMaxString = 1 << 12
+
+func writeUint64(w *bufio.Writer, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
+
+func readUint64(r *bufio.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+func writeString(w *bufio.Writer, s string) {
+	writeUint64(w, uint64(len(s)))
+	w.WriteString(s)
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	l, err := readUint64(r)
+	if err != nil {
+		return "", err
+	}
+	if l > distMaxString {
+		return "", errors.New("string too long")
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
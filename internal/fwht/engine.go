@@ -0,0 +1,240 @@
+package fwht
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Engine is a persistent worker pool for running many transforms back to back:
+// its goroutines live across stages and calls, so a stage costs no goroutine or
+// channel setup. An Engine is safe for concurrent use; each call also works on
+// its own tasks, so callers never wait for a pool busy with someone else's stage.
+type Engine struct {
+	workers        int
+	tasksPerWorker int
+
+	mu     sync.RWMutex // guards closed against dispatch
+	closed bool
+	jobs   chan *engineJob
+}
+
+// engineJob is one batch of n tasks; any goroutine holding it claims indices
+// from next until they run out.
+type engineJob struct {
+	next atomic.Int64
+	n    int
+	fn   func(i int)
+	wg   sync.WaitGroup // one count per task
+}
+
+// NewEngine starts a pool of workers goroutines (<= 0 => GOMAXPROCS(0)) that
+// split each stage into about workers·tasksPerWorker tiles (<= 0 => 3, the
+// granularity of MatVecHadamardPar). Call Close when done.
+func NewEngine(workers, tasksPerWorker int) *Engine {
+	workers = normWorkers(workers)
+	if tasksPerWorker <= 0 {
+		tasksPerWorker = defaultTasksPerWorker
+	}
+	e := &Engine{
+		workers:        workers,
+		tasksPerWorker: tasksPerWorker,
+		jobs:           make(chan *engineJob, workers),
+	}
+	// the caller is one of the workers, so the pool needs workers-1 goroutines
+	for w := 1; w < workers; w++ {
+		go func() {
+			for j := range e.jobs {
+				j.work()
+			}
+		}()
+	}
+	return e
+}
+
+// Workers is the number of goroutines (including the caller) a transform uses.
+func (e *Engine) Workers() int { return e.workers }
+
+// TasksPerWorker is the number of tiles per worker each stage is split into.
+func (e *Engine) TasksPerWorker() int { return e.tasksPerWorker }
+
+// Close stops the pool goroutines. Transforms still running finish on their
+// callers' goroutines; later calls run single-threaded.
+func (e *Engine) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.closed {
+		e.closed = true
+		close(e.jobs)
+	}
+}
+
+// MatVecHadamard is MatVecHadamardPar on the pool.
+func (e *Engine) MatVecHadamard(in []bn254.G1Affine) ([]bn254.G1Affine, error) {
+	return engineMatVecHadamard[bn254.G1Affine, bn254.G1Jac]("Engine.MatVecHadamard", e, in)
+}
+
+// MatVecHadamardScratch is MatVecHadamardParInPlaceScratch on the pool: in is
+// overwritten with H·in and no per-call vectors are allocated.
+func (e *Engine) MatVecHadamardScratch(in []bn254.G1Affine, scratch []bn254.G1Jac) error {
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return errors.New("Engine.MatVecHadamardScratch: length must be a power of two")
+	}
+	if len(scratch) < n {
+		return errors.New("Engine.MatVecHadamardScratch: scratch shorter than input")
+	}
+	buf := scratch[:n]
+	e.parallelRange(n, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&in[i])
+		}
+	})
+	engineStages[bn254.G1Jac](e, buf)
+	e.parallelRange(n, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			in[i].FromJacobian(&buf[i])
+		}
+	})
+	return nil
+}
+
+// MatVecHadamardFrInPlace is MatVecHadamardFrParInPlace on the pool.
+func (e *Engine) MatVecHadamardFrInPlace(v []fr.Element) error {
+	return engineMatVecHadamardFieldInPlace[fr.Element]("Engine.MatVecHadamardFrInPlace", e, v)
+}
+
+// GenericEngineMatVecHadamard is Engine.MatVecHadamard over any short-Weierstrass group.
+func GenericEngineMatVecHadamard[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](e *Engine, in []A) ([]A, error) {
+	return engineMatVecHadamard[A, J, PA, PJ]("GenericEngineMatVecHadamard", e, in)
+}
+
+// GenericEngineMatVecHadamardFieldInPlace is Engine.MatVecHadamardFrInPlace over any field.
+func GenericEngineMatVecHadamardFieldInPlace[E any, PE FieldElement[E]](e *Engine, v []E) error {
+	return engineMatVecHadamardFieldInPlace[E, PE]("GenericEngineMatVecHadamardFieldInPlace", e, v)
+}
+
+func engineMatVecHadamardFieldInPlace[E any, PE FieldElement[E]](name string, e *Engine, v []E) error {
+	n := len(v)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	for step := 1; step < n; step <<= 1 {
+		block := step << 1
+		e.runStage(n, step, func(t stageTask) {
+			for b := t.b0; b < t.b1; b++ {
+				base := b * block
+				for j := t.j0; j < t.j1; j++ {
+					fieldButterfly[E, PE](&v[base+j], &v[base+j+step])
+				}
+			}
+		})
+	}
+	return nil
+}
+
+func engineMatVecHadamard[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, e *Engine, in []A) ([]A, error) {
+	n := len(in)
+	if n == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	buf := make([]J, n)
+	e.parallelRange(n, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+	engineStages[J, PJ](e, buf)
+	out := make([]A, n)
+	e.parallelRange(n, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[i])
+		}
+	})
+	return out, nil
+}
+
+// engineStages is hadamardStagesPar scheduled on e.
+func engineStages[J any, PJ JacAdder[J]](e *Engine, buf []J) {
+	n := len(buf)
+	for step := 1; step < n; step <<= 1 {
+		block := step << 1
+		e.runStage(n, step, func(t stageTask) {
+			for b := t.b0; b < t.b1; b++ {
+				base := b * block
+				for j := t.j0; j < t.j1; j++ {
+					butterfly[J, PJ](&buf[base+j], &buf[base+j+step])
+				}
+			}
+		})
+	}
+}
+
+// runStage is runStage on the pool with e's task granularity.
+func (e *Engine) runStage(n, step int, fn func(t stageTask)) {
+	tasks := tileTasksN(n/(step<<1), step, e.workers*e.tasksPerWorker)
+	e.run(len(tasks), func(i int) { fn(tasks[i]) })
+}
+
+// parallelRange is parallelRange on the pool.
+func (e *Engine) parallelRange(n int, fn func(i0, i1 int)) {
+	if e.workers <= 1 || n < 1024 {
+		fn(0, n)
+		return
+	}
+	chunk := (n + e.workers - 1) / e.workers
+	e.run((n+chunk-1)/chunk, func(w int) {
+		fn(w*chunk, min((w+1)*chunk, n))
+	})
+}
+
+// run executes fn(i) for i in [0,n) on the caller plus any idle pool goroutines.
+func (e *Engine) run(n int, fn func(i int)) {
+	if n <= 1 || e.workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	j := &engineJob{n: n, fn: fn}
+	j.wg.Add(n)
+	e.mu.RLock()
+	if !e.closed {
+	dispatch:
+		for h := 1; h < min(e.workers, n); h++ {
+			select {
+			case e.jobs <- j:
+			default:
+				break dispatch // pool busy: the caller picks up the slack
+			}
+		}
+	}
+	e.mu.RUnlock()
+	j.work()
+	j.wg.Wait()
+}
+
+// work claims and runs tasks of j until none are left.
+func (j *engineJob) work() {
+	for {
+		i := int(j.next.Add(1) - 1)
+		if i >= j.n {
+			return
+		}
+		j.fn(i)
+		j.wg.Done()
+	}
+}
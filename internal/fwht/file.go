@@ -0,0 +1,304 @@
+package fwht
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// Point file format: n records of X || Y, each a 32-byte big-endian fp element
+// (no flags); (0,0) is the point at infinity, as in gnark-crypto.
+const pointFileSize = 2 * fp.Bytes
+
+// DefaultFileChunkPoints is the in-memory tile size of MatVecHadamardFile
+// (~300 bytes per point of working memory).
+const DefaultFileChunkPoints = 1 << 20
+
+// fileRunLog bounds the contiguous run length (log2, in points) read per row in
+// the strided passes; longer runs mean fewer seeks but fewer stages per pass.
+const fileRunLog = 10
+
+// FileOptions configures MatVecHadamardFile.
+type FileOptions struct {
+	Workers     int    // <= 0 => GOMAXPROCS(0)
+	ChunkPoints int    // points in memory at once, power of two >= 2; <= 0 => DefaultFileChunkPoints
+	Checkpoint  string // checkpoint path; "" => path + ".ckpt"
+}
+
+// WritePointsFile writes pts to path in the point file format.
+func WritePointsFile(path string, pts []bn254.G1Affine) error {
+	raw := make([]byte, len(pts)*pointFileSize)
+	encodePoints(raw, pts, 0)
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// ReadPointsFile reads a point file written by WritePointsFile or MatVecHadamardFile.
+func ReadPointsFile(path string) ([]bn254.G1Affine, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%pointFileSize != 0 {
+		return nil, fmt.Errorf("ReadPointsFile: size %d is not a multiple of %d", len(raw), pointFileSize)
+	}
+	pts := make([]bn254.G1Affine, len(raw)/pointFileSize)
+	if err := decodePoints(pts, raw, 0); err != nil {
+		return nil, fmt.Errorf("ReadPointsFile: %w", err)
+	}
+	return pts, nil
+}
+
+// MatVecHadamardFile overwrites the point file at path with H·x without loading it:
+//   - the first pass runs the low log2(chunk) stages on contiguous chunks;
+//   - each later pass gathers 2^m rows of 2^fileRunLog contiguous points
+//     (stride 2^s) and runs stages [s, s+m) on them in memory.
+//
+// Every pass reads one file and writes the other of path / path+".tmp", then
+// records the completed stage count in the checkpoint file, so a rerun after a
+// crash resumes from the last completed pass (the chunk size of the checkpoint
+// wins over opts). On success the result is in path and both helpers are removed.
+func MatVecHadamardFile(path string, opts FileOptions) error {
+	return matVecHadamardFile("MatVecHadamardFile", context.Background(), path, opts, nil)
+}
+
+// MatVecHadamardFileCtx is MatVecHadamardFile that checks ctx before every pass
+// and reports each saved checkpoint to fn (may be nil) as the last completed
+// stage and the butterflies done so far. On cancellation it returns ctx.Err()
+// with the checkpoint in place, so a later call resumes from it.
+func MatVecHadamardFileCtx(ctx context.Context, path string, opts FileOptions, fn ProgressFunc) error {
+	return matVecHadamardFile("MatVecHadamardFileCtx", ctx, path, opts, fn)
+}
+
+func matVecHadamardFile(name string, ctx context.Context, path string, opts FileOptions, fn ProgressFunc) error {
+	st, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if st.Size()%pointFileSize != 0 {
+		return fmt.Errorf("%s: size %d is not a multiple of %d", name, st.Size(), pointFileSize)
+	}
+	n := int(st.Size() / pointFileSize)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	chunk := opts.ChunkPoints
+	if chunk <= 0 {
+		chunk = DefaultFileChunkPoints
+	}
+	if chunk < 2 || !isPowerOfTwo(chunk) {
+		return fmt.Errorf("%s: chunk must be a power of two >= 2", name)
+	}
+	chunk = min(chunk, n)
+	ckptPath := opts.Checkpoint
+	if ckptPath == "" {
+		ckptPath = path + ".ckpt"
+	}
+	workers := normWorkers(opts.Workers)
+
+	ck := fileCheckpoint{n: n, chunk: chunk}
+	found, err := loadFileCheckpoint(ckptPath, &ck)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if found && ck.n != n {
+		return fmt.Errorf("%s: checkpoint is for %d points, file has %d", name, ck.n, n)
+	}
+
+	files := [2]string{path, path + ".tmp"}
+	logN := bits.Len(uint(n)) - 1
+	logC := bits.Len(uint(ck.chunk)) - 1
+	for ck.stages < logN {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		m := filePassStages(ck.stages, logN, logC)
+		if err := filePass(files[ck.cur], files[1-ck.cur], n, ck.stages, m, ck.chunk, workers); err != nil {
+			return fmt.Errorf("%s: stages [%d,%d): %w", name, ck.stages, ck.stages+m, err)
+		}
+		ck.stages += m
+		ck.cur = 1 - ck.cur
+		if err := saveFileCheckpoint(ckptPath, ck); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if fn != nil {
+			fn(ck.stages-1, int64(ck.stages)*int64(n/2))
+		}
+	}
+
+	if ck.cur == 1 {
+		// a missing .tmp means a previous run already renamed it
+		if err := os.Rename(files[1], path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	} else if err := os.Remove(files[1]); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// n = 1 has no stages, so no checkpoint was written
+	if err := os.Remove(ckptPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// filePassStages returns how many stages the pass starting at stage s0 runs.
+func filePassStages(s0, logN, logC int) int {
+	if s0 == 0 {
+		return min(logC, logN)
+	}
+	runLog := min(fileRunLog, logC/2)
+	return min(logC-runLog, logN-s0)
+}
+
+// filePass reads src, applies stages [s0, s0+m) and writes the result to dst.
+// A tile is the 2^m rows t of L = chunk>>m points at offsets hi·2^(s0+m) + t·2^s0 + lo;
+// in memory row t starts at t·L, so stage s0+k is the in-memory stage with step L<<k.
+func filePass(srcPath, dstPath string, n, s0, m, chunk, workers int) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(dstPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	if err := dst.Truncate(int64(n) * pointFileSize); err != nil {
+		return err
+	}
+
+	L := chunk >> m
+	rows, seg := 1<<m, L*pointFileSize
+	if L == 1<<s0 {
+		// rows are adjacent: one contiguous read per tile
+		rows, seg = 1, chunk*pointFileSize
+	}
+	raw := make([]byte, chunk*pointFileSize)
+	buf := make([]bn254.G1Jac, chunk)
+	aff := make([]bn254.G1Affine, chunk)
+
+	for hi := 0; hi < n>>(s0+m); hi++ {
+		for lo := 0; lo < 1<<s0; lo += L {
+			base := int64(hi<<(s0+m)|lo) * pointFileSize
+			stride := int64(1<<s0) * pointFileSize
+			for t := 0; t < rows; t++ {
+				if _, err := src.ReadAt(raw[t*seg:(t+1)*seg], base+int64(t)*stride); err != nil {
+					if err == io.EOF {
+						err = io.ErrUnexpectedEOF
+					}
+					return err
+				}
+			}
+			if err := decodePoints(aff, raw, workers); err != nil {
+				return err
+			}
+			parallelRange(chunk, workers, func(i0, i1 int) {
+				for i := i0; i < i1; i++ {
+					buf[i].FromAffine(&aff[i])
+				}
+			})
+			for k := 0; k < m; k++ {
+				hadamardStagePar(buf, L<<k, workers)
+			}
+			encodePoints(raw, BatchJacToAffG1Par(buf, workers), workers)
+			for t := 0; t < rows; t++ {
+				if _, err := dst.WriteAt(raw[t*seg:(t+1)*seg], base+int64(t)*stride); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return dst.Sync()
+}
+
+// encodePoints writes pts into raw in the point file format.
+func encodePoints(raw []byte, pts []bn254.G1Affine, workers int) {
+	parallelRange(len(pts), workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			r := raw[i*pointFileSize:]
+			fp.BigEndian.PutElement((*[fp.Bytes]byte)(r[:fp.Bytes]), pts[i].X)
+			fp.BigEndian.PutElement((*[fp.Bytes]byte)(r[fp.Bytes:pointFileSize]), pts[i].Y)
+		}
+	})
+}
+
+// decodePoints parses raw into pts, rejecting non-canonical coordinates.
+func decodePoints(pts []bn254.G1Affine, raw []byte, workers int) error {
+	var bad atomic.Int64 // first bad index + 1
+	parallelRange(len(pts), workers, func(i0, i1 int) {
+		var err error
+		for i := i0; i < i1; i++ {
+			r := raw[i*pointFileSize:]
+			if pts[i].X, err = fp.BigEndian.Element((*[fp.Bytes]byte)(r[:fp.Bytes])); err == nil {
+				pts[i].Y, err = fp.BigEndian.Element((*[fp.Bytes]byte)(r[fp.Bytes:pointFileSize]))
+			}
+			if err != nil {
+				bad.CompareAndSwap(0, int64(i)+1)
+				return
+			}
+		}
+	})
+	if i := bad.Load(); i != 0 {
+		return fmt.Errorf("point %d: non-canonical coordinate", i-1)
+	}
+	return nil
+}
+
+// fileCheckpoint is the resume state of MatVecHadamardFile: stages completed and
+// which of path (0) / path+".tmp" (1) holds them.
+type fileCheckpoint struct {
+	n, chunk, stages, cur int
+}
+
+const fileCheckpointMagic = "fwht-file-ckpt-v1"
+
+// loadFileCheckpoint fills ck from path; found is false if path does not exist.
+func loadFileCheckpoint(path string, ck *fileCheckpoint) (found bool, err error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var magic string
+	var c fileCheckpoint
+	if _, err := fmt.Sscanf(string(b), "%s %d %d %d %d", &magic, &c.n, &c.chunk, &c.stages, &c.cur); err != nil || magic != fileCheckpointMagic {
+		return false, fmt.Errorf("bad checkpoint %s", path)
+	}
+	if c.chunk < 2 || !isPowerOfTwo(c.chunk) || c.stages < 0 || (c.cur != 0 && c.cur != 1) {
+		return false, fmt.Errorf("bad checkpoint %s", path)
+	}
+	*ck = c
+	return true, nil
+}
+
+// saveFileCheckpoint replaces path with ck atomically (write + fsync + rename).
+func saveFileCheckpoint(path string, ck fileCheckpoint) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "%s %d %d %d %d\n", fileCheckpointMagic, ck.n, ck.chunk, ck.stages, ck.cur)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
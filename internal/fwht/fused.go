@@ -0,0 +1,114 @@
+package fwht
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// DefaultTileBytes is the working-set size of one cache-blocked tile (≈ L2).
+const DefaultTileBytes = 1 << 20
+
+// MatVecHadamardParRadix4 is MatVecHadamardPar fusing two stages per pass:
+// each pass loads 4 points (distance step) and applies both butterfly levels,
+// halving the number of sweeps over the Jacobian buffer. An odd last stage runs radix-2.
+func MatVecHadamardParRadix4(in []bn254.G1Affine, workers int) ([]bn254.G1Affine, error) {
+	return matVecHadamardParWith[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardParRadix4", in, workers,
+		func(buf []bn254.G1Jac, workers int) {
+			radix4StagesPar(buf, 1, workers)
+		})
+}
+
+// MatVecHadamardParBlocked is MatVecHadamardPar with cache blocking: the low stages
+// (step < tilePoints) run tile by tile, each tile staying in cache for all of them,
+// then the remaining high stages run as radix-4 passes over the whole buffer.
+// tilePoints <= 0 => DefaultTileBytes worth of G1Jac; it is rounded down to a power of two.
+func MatVecHadamardParBlocked(in []bn254.G1Affine, workers, tilePoints int) ([]bn254.G1Affine, error) {
+	if tilePoints <= 0 {
+		tilePoints = DefaultTileBytes / sizeofG1Jac
+	}
+	return matVecHadamardParWith[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardParBlocked", in, workers,
+		func(buf []bn254.G1Jac, workers int) {
+			blockedStagesPar(buf, tilePoints, workers)
+		})
+}
+
+const sizeofG1Jac = 3 * 32
+
+// matVecHadamardParWith is matVecHadamardPar with the butterfly stages supplied by stages.
+func matVecHadamardParWith[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, workers int, stages func(buf []J, workers int)) ([]A, error) {
+	n := len(in)
+	if n == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	workers = normWorkers(workers)
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+
+	stages(buf, workers)
+
+	out := make([]A, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[i])
+		}
+	})
+	return out, nil
+}
+
+// radix4StagesPar runs every stage with distance >= fromStep, two stages per pass.
+func radix4StagesPar[J any, PJ JacAdder[J]](buf []J, fromStep, workers int) {
+	n := len(buf)
+	step := fromStep
+	for ; step<<2 <= n; step <<= 2 {
+		block := step << 2
+		s := step
+		runTasks(tileTasks(n/block, step, workers), workers, func(t stageTask) {
+			for b := t.b0; b < t.b1; b++ {
+				base := b * block
+				for j := t.j0; j < t.j1; j++ {
+					p0 := &buf[base+j]
+					p1 := &buf[base+j+s]
+					p2 := &buf[base+j+2*s]
+					p3 := &buf[base+j+3*s]
+					butterfly[J, PJ](p0, p1)
+					butterfly[J, PJ](p2, p3)
+					butterfly[J, PJ](p0, p2)
+					butterfly[J, PJ](p1, p3)
+				}
+			}
+		})
+	}
+	if step < n {
+		hadamardStagePar[J, PJ](buf, step, workers)
+	}
+}
+
+// blockedStagesPar runs the stages with step < tile inside independent tiles of
+// `tile` points (in parallel across tiles), then the rest with radix4StagesPar.
+func blockedStagesPar[J any, PJ JacAdder[J]](buf []J, tile, workers int) {
+	n := len(buf)
+	for tile&(tile-1) != 0 {
+		tile &= tile - 1
+	}
+	if tile < 2 {
+		tile = 2
+	}
+	if tile >= n || n/tile < workers {
+		// 타일 수가 코어보다 적으면 타일 병렬화 이득이 없다
+		radix4StagesPar[J, PJ](buf, 1, workers)
+		return
+	}
+	parallelTasks(n/tile, workers, func(t int) {
+		hadamardStagesSerial[J, PJ](buf[t*tile : (t+1)*tile])
+	})
+	radix4StagesPar[J, PJ](buf, tile, workers)
+}
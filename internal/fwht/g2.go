@@ -0,0 +1,79 @@
+package fwht
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// MatVecHadamardParG2 is MatVecHadamardPar over BN254 G2.
+// - len(in) must be a power of two
+// - workers <= 0 => use GOMAXPROCS(0)
+func MatVecHadamardParG2(in []bn254.G2Affine, workers int) ([]bn254.G2Affine, error) {
+	return matVecHadamardPar[bn254.G2Affine, bn254.G2Jac]("MatVecHadamardParG2", in, workers)
+}
+
+// MatVecHadamardSerialInPlaceG2 is MatVecHadamardSerialInPlace over BN254 G2.
+func MatVecHadamardSerialInPlaceG2(in []bn254.G2Affine) error {
+	return matVecHadamardSerialInPlace[bn254.G2Affine, bn254.G2Jac]("MatVecHadamardSerialInPlaceG2", in)
+}
+
+// BatchJacToAffG2Par converts G2 Jacobian points to affine with a single E2
+// inversion (Montgomery batch trick); the per-point X/Z², Y/Z³ step runs in parallel.
+// Points with Z = 0 map to the affine infinity (0,0).
+func BatchJacToAffG2Par(in []bn254.G2Jac, workers int) []bn254.G2Affine {
+	n := len(in)
+	out := make([]bn254.G2Affine, n)
+	if n == 0 {
+		return out
+	}
+	workers = normWorkers(workers)
+
+	// 1) Z != 0 인덱스만 모은다 (Z=0 → ∞)
+	nonZero := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if in[i].Z.IsZero() {
+			out[i].SetInfinity()
+		} else {
+			nonZero = append(nonZero, i)
+		}
+	}
+	k := len(nonZero)
+	if k == 0 {
+		return out
+	}
+
+	// 2) prefix products P[j] = ∏_{t=0..j} Z_t
+	acc := make([]bn254.E2, k)
+	acc[0] = in[nonZero[0]].Z
+	for j := 1; j < k; j++ {
+		acc[j].Mul(&acc[j-1], &in[nonZero[j]].Z)
+	}
+
+	// 3) 1/∏Z 한 번만 계산
+	var invAll bn254.E2
+	invAll.Inverse(&acc[k-1])
+
+	// 4) 역전파로 1/Z_j 산출
+	invZ := make([]bn254.E2, k)
+	for j := k - 1; j >= 0; j-- {
+		if j == 0 {
+			invZ[0] = invAll
+		} else {
+			invZ[j].Mul(&invAll, &acc[j-1])
+		}
+		invAll.Mul(&invAll, &in[nonZero[j]].Z)
+	}
+
+	// 5) Affine 좌표 계산 병렬화
+	parallelRange(k, workers, func(i0, i1 int) {
+		var inv2, inv3 bn254.E2
+		for j := i0; j < i1; j++ {
+			i := nonZero[j]
+			inv2.Square(&invZ[j])
+			inv3.Mul(&inv2, &invZ[j])
+			out[i].X.Mul(&in[i].X, &inv2)
+			out[i].Y.Mul(&in[i].Y, &inv3)
+		}
+	})
+
+	return out
+}
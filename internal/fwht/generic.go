@@ -0,0 +1,135 @@
+package fwht
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"runtime"
+)
+
+// JacAdder is satisfied by *J for any gnark-crypto Jacobian point type J
+// (bn254.G1Jac, bls12381.G1Jac, bn254.G2Jac, ...).
+type JacAdder[J any] interface {
+	*J
+	AddAssign(*J) *J
+	SubAssign(*J) *J
+}
+
+// JacPoint is JacAdder plus conversion from the affine type A and scalar multiplication.
+type JacPoint[J, A any] interface {
+	JacAdder[J]
+	FromAffine(*A) *J
+	ScalarMultiplication(*J, *big.Int) *J
+}
+
+// AffPoint is satisfied by *A for the affine counterpart A of Jacobian type J.
+type AffPoint[A, J any] interface {
+	*A
+	FromJacobian(*J) *A
+}
+
+// GenericMatVecHadamardPar is MatVecHadamardPar over any short-Weierstrass group
+// given as an affine/Jacobian pair, e.g.
+//
+//	GenericMatVecHadamardPar[bls12381.G1Affine, bls12381.G1Jac](in, workers)
+func GenericMatVecHadamardPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, workers int) ([]A, error) {
+	return matVecHadamardPar[A, J, PA, PJ]("GenericMatVecHadamardPar", in, workers)
+}
+
+// GenericMatVecHadamardSerialInPlace is MatVecHadamardSerialInPlace over any
+// short-Weierstrass group given as an affine/Jacobian pair.
+func GenericMatVecHadamardSerialInPlace[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A) error {
+	return matVecHadamardSerialInPlace[A, J, PA, PJ]("GenericMatVecHadamardSerialInPlace", in)
+}
+
+// matVecHadamardPar is the shared body of the parallel transforms; name prefixes errors.
+func matVecHadamardPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, workers int) ([]A, error) {
+	// NormNone never reduces by the group order, so none is needed.
+	return matVecHadamardParOpts[A, J, PA, PJ](name, in, nil, Options{Workers: workers})
+}
+
+// matVecHadamardSerialInPlace is the shared body of the serial transforms.
+func matVecHadamardSerialInPlace[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A) error {
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+
+	// 1) Affine -> Jacobian
+	buf := make([]J, n)
+	for i := 0; i < n; i++ {
+		PJ(&buf[i]).FromAffine(&in[i])
+	}
+
+	// 2) FWHT
+	hadamardStagesSerial[J, PJ](buf)
+
+	// 3) Jacobian -> Affine (in-place overwrite)
+	for i := 0; i < n; i++ {
+		PA(&in[i]).FromJacobian(&buf[i])
+	}
+	return nil
+}
+
+// hadamardStagesPar runs all log2(n) butterfly stages on buf in place.
+func hadamardStagesPar[J any, PJ JacAdder[J]](buf []J, workers int) {
+	for step := 1; step < len(buf); step <<= 1 {
+		hadamardStagePar[J, PJ](buf, step, workers)
+	}
+}
+
+// hadamardStagePar runs the single stage with butterfly distance step.
+func hadamardStagePar[J any, PJ JacAdder[J]](buf []J, step, workers int) {
+	block := step << 1
+	runStage(len(buf), step, workers, func(t stageTask) {
+		for b := t.b0; b < t.b1; b++ {
+			base := b * block
+			for j := t.j0; j < t.j1; j++ {
+				butterfly[J, PJ](&buf[base+j], &buf[base+j+step])
+			}
+		}
+	})
+}
+
+// hadamardStagesSerial runs all stages on a single goroutine using only the
+// stage index r and butterfly index k:
+//
+//	aIdx = ((k >> r) << (r+1)) | (k & ((1<<r)-1))
+//	cIdx = aIdx + (1 << r)
+func hadamardStagesSerial[J any, PJ JacAdder[J]](buf []J) {
+	n := len(buf)
+	stages := bits.Len(uint(n)) - 1 // log2(n)
+	half := n >> 1                  // total butterflies per stage
+
+	for r := 0; r < stages; r++ {
+		mask := (1 << r) - 1
+		dist := 1 << r
+		for k := 0; k < half; k++ {
+			aIdx := ((k >> r) << (r + 1)) | (k & mask)
+			butterfly[J, PJ](&buf[aIdx], &buf[aIdx+dist])
+		}
+	}
+}
+
+// butterfly sets (a, c) <- (a+c, a-c).
+func butterfly[J any, PJ JacAdder[J]](a, c *J) {
+	ta := *a
+	tc := *c
+	PJ(a).AddAssign(&tc)
+	*c = ta
+	PJ(c).SubAssign(&tc)
+}
+
+// normWorkers maps workers <= 0 to GOMAXPROCS(0).
+func normWorkers(workers int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
@@ -0,0 +1,64 @@
+package fwht
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+// MatVecHadamardParInPlace overwrites in with H·in without a Jacobian copy of the
+// vector: every stage runs in affine coordinates directly on in (see
+// MatVecHadamardParAffine), so the only extra memory is n field elements of
+// batch-inversion scratch (~1/3 of a []G1Jac).
+func MatVecHadamardParInPlace(in []bn254.G1Affine, workers int) error {
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return errors.New("MatVecHadamardParInPlace: length must be a power of two")
+	}
+	workers = normWorkers(workers)
+
+	den := make([]fp.Element, n/2)
+	prefix := make([]fp.Element, n/2)
+	for s := 0; s < bits.Len(uint(n))-1; s++ {
+		affineStagePar(in, 1<<s, workers, den, prefix)
+	}
+	return nil
+}
+
+// MatVecHadamardParInPlaceScratch overwrites in with H·in using the caller's
+// Jacobian buffer (len(scratch) >= len(in)) for the stages, so repeated transforms
+// allocate no per-call vectors. scratch contents are clobbered.
+func MatVecHadamardParInPlaceScratch(in []bn254.G1Affine, scratch []bn254.G1Jac, workers int) error {
+	n := len(in)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return errors.New("MatVecHadamardParInPlaceScratch: length must be a power of two")
+	}
+	if len(scratch) < n {
+		return errors.New("MatVecHadamardParInPlaceScratch: scratch shorter than input")
+	}
+	workers = normWorkers(workers)
+
+	buf := scratch[:n]
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&in[i])
+		}
+	})
+
+	hadamardStagesPar(buf, workers)
+
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			in[i].FromJacobian(&buf[i])
+		}
+	})
+	return nil
+}
@@ -0,0 +1,196 @@
+package fwht
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Kernel is a 2×2 scalar matrix [[A, B], [C, D]]. The Kronecker transform with
+// kernel K multiplies by K^{⊗log2 n}: every butterfly maps (x, y) -> (A·x + B·y, C·x + D·y).
+// Entries are reduced mod the group order / field modulus; negative values are allowed.
+type Kernel struct{ A, B, C, D *big.Int }
+
+// NewKernel returns the kernel [[a, b], [c, d]].
+func NewKernel(a, b, c, d int64) Kernel {
+	return Kernel{big.NewInt(a), big.NewInt(b), big.NewInt(c), big.NewInt(d)}
+}
+
+// HadamardKernel returns [[1, 1], [1, -1]]; its Kronecker power is H.
+func HadamardKernel() Kernel {
+	return NewKernel(1, 1, 1, -1)
+}
+
+// KroneckerPar applies K^{⊗log2 n} to a BN254 G1 vector (len(in) a power of two).
+// The Hadamard kernel takes the MatVecHadamardPar add/sub fast path.
+func KroneckerPar(in []bn254.G1Affine, k Kernel, workers int) ([]bn254.G1Affine, error) {
+	return kroneckerPar[bn254.G1Affine, bn254.G1Jac]("KroneckerPar", in, k, fr.Modulus(), workers)
+}
+
+// KroneckerFrParInPlace applies K^{⊗log2 n} to a scalar vector in place.
+func KroneckerFrParInPlace(v []fr.Element, k Kernel, workers int) error {
+	return kroneckerFieldParInPlace[fr.Element]("KroneckerFrParInPlace", v, k, fr.Modulus(), workers)
+}
+
+// GenericKroneckerPar is KroneckerPar over any group of order `order`.
+func GenericKroneckerPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, k Kernel, order *big.Int, workers int) ([]A, error) {
+	return kroneckerPar[A, J, PA, PJ]("GenericKroneckerPar", in, k, order, workers)
+}
+
+// GenericKroneckerFieldParInPlace is KroneckerFrParInPlace over any field.
+func GenericKroneckerFieldParInPlace[E any, PE FieldElement[E]](v []E, k Kernel, modulus *big.Int, workers int) error {
+	return kroneckerFieldParInPlace[E, PE]("GenericKroneckerFieldParInPlace", v, k, modulus, workers)
+}
+
+// coefKind classifies a reduced kernel entry so group butterflies can skip
+// scalar multiplications for 0 and ±1.
+type coefKind int
+
+const (
+	coefZero coefKind = iota
+	coefOne
+	coefMinusOne
+	coefGeneral
+)
+
+// reducedCoef is one kernel entry reduced mod the order.
+type reducedCoef struct {
+	kind coefKind
+	v    *big.Int
+}
+
+func reduceCoef(x, order *big.Int) reducedCoef {
+	v := new(big.Int).Mod(x, order)
+	minusOne := new(big.Int).Sub(order, big.NewInt(1))
+	switch {
+	case v.Sign() == 0:
+		return reducedCoef{kind: coefZero, v: v}
+	case v.IsInt64() && v.Int64() == 1:
+		return reducedCoef{kind: coefOne, v: v}
+	case v.Cmp(minusOne) == 0:
+		return reducedCoef{kind: coefMinusOne, v: v}
+	default:
+		return reducedCoef{kind: coefGeneral, v: v}
+	}
+}
+
+// reduceKernel returns the reduced entries [A, B, C, D] and whether k ≡ Hadamard.
+func reduceKernel(k Kernel, order *big.Int) ([4]reducedCoef, bool, error) {
+	var rc [4]reducedCoef
+	for i, x := range []*big.Int{k.A, k.B, k.C, k.D} {
+		if x == nil {
+			return rc, false, fmt.Errorf("kernel entry %d is nil", i)
+		}
+		rc[i] = reduceCoef(x, order)
+	}
+	isHadamard := rc[0].kind == coefOne && rc[1].kind == coefOne &&
+		rc[2].kind == coefOne && rc[3].kind == coefMinusOne
+	return rc, isHadamard, nil
+}
+
+func kroneckerPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, k Kernel, order *big.Int, workers int) ([]A, error) {
+	n := len(in)
+	if n == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	rc, isHadamard, err := reduceKernel(k, order)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if isHadamard {
+		return matVecHadamardPar[A, J, PA, PJ](name, in, workers)
+	}
+	workers = normWorkers(workers)
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+
+	runStages(buf, workers, func(a, c *J) {
+		x, y := *a, *c
+		*a = linComb[J, A, PJ](rc[0], &x, rc[1], &y)
+		*c = linComb[J, A, PJ](rc[2], &x, rc[3], &y)
+	})
+
+	out := make([]A, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[i])
+		}
+	})
+	return out, nil
+}
+
+// linComb returns a·x + b·y.
+func linComb[J, A any, PJ JacPoint[J, A]](a reducedCoef, x *J, b reducedCoef, y *J) J {
+	acc := scaleCoef[J, A, PJ](a, x)
+	switch b.kind {
+	case coefZero:
+	case coefOne:
+		PJ(&acc).AddAssign(y)
+	case coefMinusOne:
+		PJ(&acc).SubAssign(y)
+	default:
+		t := scaleCoef[J, A, PJ](b, y)
+		PJ(&acc).AddAssign(&t)
+	}
+	return acc
+}
+
+// scaleCoef returns c·x; the zero value of J is the identity (Z = 0).
+func scaleCoef[J, A any, PJ JacPoint[J, A]](c reducedCoef, x *J) J {
+	var r J
+	switch c.kind {
+	case coefOne:
+		r = *x
+	case coefMinusOne:
+		PJ(&r).SubAssign(x)
+	case coefGeneral:
+		PJ(&r).ScalarMultiplication(x, c.v)
+	}
+	return r
+}
+
+func kroneckerFieldParInPlace[E any, PE FieldElement[E]](name string, v []E, k Kernel, modulus *big.Int, workers int) error {
+	n := len(v)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	rc, isHadamard, err := reduceKernel(k, modulus)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	workers = normWorkers(workers)
+	if isHadamard {
+		hadamardFieldStagesPar[E, PE](v, workers)
+		return nil
+	}
+
+	var ka, kb, kc, kd E
+	PE(&ka).SetBigInt(rc[0].v)
+	PE(&kb).SetBigInt(rc[1].v)
+	PE(&kc).SetBigInt(rc[2].v)
+	PE(&kd).SetBigInt(rc[3].v)
+
+	runStages(v, workers, func(a, c *E) {
+		var t0, t1, t2, t3 E
+		PE(&t0).Mul(&ka, a)
+		PE(&t1).Mul(&kb, c)
+		PE(&t2).Mul(&kc, a)
+		PE(&t3).Mul(&kd, c)
+		PE(a).Add(&t0, &t1)
+		PE(c).Add(&t2, &t3)
+	})
+	return nil
+}
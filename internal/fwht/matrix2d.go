@@ -0,0 +1,103 @@
+package fwht
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Axes2D selects the passes of a 2D transform of a row-major rows×cols matrix M.
+type Axes2D int
+
+const (
+	// Axes2DBoth is H·M·Hᵀ (row pass then column pass).
+	Axes2DBoth Axes2D = iota
+	// Axes2DRows is M·Hᵀ: every row transformed.
+	Axes2DRows
+	// Axes2DCols is H·M: every column transformed.
+	Axes2DCols
+)
+
+// MatVecHadamard2DPar computes the 2D transform of the row-major rows×cols point
+// matrix m (both powers of two). The row pass is the stages with step < cols; the
+// column pass is the stages with step cols·2^k, which pair row i with row i+2^k
+// elementwise, so no transpose is needed. H·M·Hᵀ flattened equals MatVecHadamardPar
+// of the flat vector (H_rows ⊗ H_cols = H_{rows·cols}).
+func MatVecHadamard2DPar(m []bn254.G1Affine, rows, cols int, axes Axes2D, workers int) ([]bn254.G1Affine, error) {
+	return matVecHadamard2DPar[bn254.G1Affine, bn254.G1Jac]("MatVecHadamard2DPar", m, rows, cols, axes, workers)
+}
+
+// MatVecHadamard2DFrParInPlace is MatVecHadamard2DPar over a scalar matrix, in place.
+func MatVecHadamard2DFrParInPlace(m []fr.Element, rows, cols int, axes Axes2D, workers int) error {
+	return matVecHadamard2DFieldParInPlace[fr.Element]("MatVecHadamard2DFrParInPlace", m, rows, cols, axes, workers)
+}
+
+// GenericMatVecHadamard2DPar is MatVecHadamard2DPar over any short-Weierstrass group.
+func GenericMatVecHadamard2DPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](m []A, rows, cols int, axes Axes2D, workers int) ([]A, error) {
+	return matVecHadamard2DPar[A, J, PA, PJ]("GenericMatVecHadamard2DPar", m, rows, cols, axes, workers)
+}
+
+// GenericMatVecHadamard2DFieldParInPlace is MatVecHadamard2DFrParInPlace over any field.
+func GenericMatVecHadamard2DFieldParInPlace[E any, PE FieldElement[E]](m []E, rows, cols int, axes Axes2D, workers int) error {
+	return matVecHadamard2DFieldParInPlace[E, PE]("GenericMatVecHadamard2DFieldParInPlace", m, rows, cols, axes, workers)
+}
+
+func matVecHadamard2DPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, m []A, rows, cols int, axes Axes2D, workers int) ([]A, error) {
+	lo, hi, err := check2D(m, rows, cols, axes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	n := len(m)
+	workers = normWorkers(workers)
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&m[i])
+		}
+	})
+	for step := lo; step < hi; step <<= 1 {
+		hadamardStagePar[J, PJ](buf, step, workers)
+	}
+	out := make([]A, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[i])
+		}
+	})
+	return out, nil
+}
+
+func matVecHadamard2DFieldParInPlace[E any, PE FieldElement[E]](name string, m []E, rows, cols int, axes Axes2D, workers int) error {
+	lo, hi, err := check2D(m, rows, cols, axes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	workers = normWorkers(workers)
+	for step := lo; step < hi; step <<= 1 {
+		hadamardFieldStagePar[E, PE](m, step, workers)
+	}
+	return nil
+}
+
+// check2D validates the matrix shape and returns the stage steps [lo, hi) of axes.
+func check2D[T any](m []T, rows, cols int, axes Axes2D) (lo, hi int, err error) {
+	if !isPowerOfTwo(rows) || !isPowerOfTwo(cols) {
+		return 0, 0, errors.New("rows and cols must be powers of two")
+	}
+	if len(m) != rows*cols {
+		return 0, 0, fmt.Errorf("len(m) = %d, want rows*cols = %d", len(m), rows*cols)
+	}
+	switch axes {
+	case Axes2DBoth:
+		return 1, rows * cols, nil
+	case Axes2DRows:
+		return 1, cols, nil
+	case Axes2DCols:
+		return cols, rows * cols, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown axes %d", axes)
+	}
+}
@@ -0,0 +1,195 @@
+package fwht
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// MatVecHadamardParOpts is MatVecHadamardPar with Options (workers, normalization, ordering).
+func MatVecHadamardParOpts(in []bn254.G1Affine, opts Options) ([]bn254.G1Affine, error) {
+	return matVecHadamardParOpts[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardParOpts", in, fr.Modulus(), opts)
+}
+
+// InvMatVecHadamardPar computes H⁻¹·x = n⁻¹·H·x, undoing MatVecHadamardPar.
+func InvMatVecHadamardPar(in []bn254.G1Affine, workers int) ([]bn254.G1Affine, error) {
+	return matVecHadamardParOpts[bn254.G1Affine, bn254.G1Jac]("InvMatVecHadamardPar", in, fr.Modulus(), Options{Workers: workers, Norm: NormInverse})
+}
+
+// ScaledMatVecHadamardPar computes (1/√n)·H·x, the self-inverse (orthonormal-style) transform.
+func ScaledMatVecHadamardPar(in []bn254.G1Affine, workers int) ([]bn254.G1Affine, error) {
+	return matVecHadamardParOpts[bn254.G1Affine, bn254.G1Jac]("ScaledMatVecHadamardPar", in, fr.Modulus(), Options{Workers: workers, Norm: NormOrthonormal})
+}
+
+// MatVecHadamardFrInPlaceOpts is MatVecHadamardFrParInPlace with Options. Of the
+// pad policies only PadError and PadTruncate (zero padding) apply in place.
+func MatVecHadamardFrInPlaceOpts(v []fr.Element, opts Options) error {
+	return matVecHadamardFieldInPlaceOpts[fr.Element]("MatVecHadamardFrInPlaceOpts", v, fr.Modulus(), opts)
+}
+
+// InvMatVecHadamardFrParInPlace computes v <- n⁻¹·H·v.
+func InvMatVecHadamardFrParInPlace(v []fr.Element, workers int) error {
+	return matVecHadamardFieldInPlaceOpts[fr.Element]("InvMatVecHadamardFrParInPlace", v, fr.Modulus(), Options{Workers: workers, Norm: NormInverse})
+}
+
+// ScaledMatVecHadamardFrParInPlace computes v <- (1/√n)·H·v.
+func ScaledMatVecHadamardFrParInPlace(v []fr.Element, workers int) error {
+	return matVecHadamardFieldInPlaceOpts[fr.Element]("ScaledMatVecHadamardFrParInPlace", v, fr.Modulus(), Options{Workers: workers, Norm: NormOrthonormal})
+}
+
+// MatVecHadamardParPad is MatVecHadamardParOpts that also reports the padding
+// applied under opts.Pad (row i < InputLen of the output is row i of H_n·pad(in)).
+func MatVecHadamardParPad(in []bn254.G1Affine, opts Options) ([]bn254.G1Affine, PadInfo, error) {
+	return matVecHadamardParPad[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardParPad", in, fr.Modulus(), opts)
+}
+
+// GenericMatVecHadamardParPad is MatVecHadamardParPad over any group of order order.
+func GenericMatVecHadamardParPad[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, order *big.Int, opts Options) ([]A, PadInfo, error) {
+	return matVecHadamardParPad[A, J, PA, PJ]("GenericMatVecHadamardParPad", in, order, opts)
+}
+
+// GenericMatVecHadamardParOpts is MatVecHadamardParOpts over any group; order is the
+// group order r the normalization scale is reduced by (e.g. bls12381/fr.Modulus()).
+func GenericMatVecHadamardParOpts[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, order *big.Int, opts Options) ([]A, error) {
+	return matVecHadamardParOpts[A, J, PA, PJ]("GenericMatVecHadamardParOpts", in, order, opts)
+}
+
+// GenericMatVecHadamardFieldInPlaceOpts is MatVecHadamardFrInPlaceOpts over any field;
+// modulus is the field characteristic.
+func GenericMatVecHadamardFieldInPlaceOpts[E any, PE FieldElement[E]](v []E, modulus *big.Int, opts Options) error {
+	return matVecHadamardFieldInPlaceOpts[E, PE]("GenericMatVecHadamardFieldInPlaceOpts", v, modulus, opts)
+}
+
+func matVecHadamardParOpts[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, order *big.Int, opts Options) ([]A, error) {
+	out, _, err := matVecHadamardParPad[A, J, PA, PJ](name, in, order, opts)
+	return out, err
+}
+
+func matVecHadamardParPad[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, order *big.Int, opts Options) ([]A, PadInfo, error) {
+	in, info, err := padInput(in, opts)
+	if err != nil {
+		return nil, info, fmt.Errorf("%s: %w", name, err)
+	}
+	n := len(in)
+	if n == 0 {
+		return nil, info, nil
+	}
+	scale, err := normScale(n, opts.Norm, order)
+	if err == nil {
+		err = checkOrdering(opts.Order)
+	}
+	if err != nil {
+		return nil, info, fmt.Errorf("%s: %w", name, err)
+	}
+	workers := normWorkers(opts.Workers)
+	logN := bits.Len(uint(n)) - 1
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+
+	hadamardStagesPar[J, PJ](buf, workers)
+	if scale != nil {
+		scaleJacPar[J, A, PJ](buf, scale, workers)
+	}
+
+	// Jacobian -> Affine, gathering rows into the requested order
+	out := make([]A, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PA(&out[i]).FromJacobian(&buf[RowToNatural(opts.Order, i, logN)])
+		}
+	})
+	return out[:info.OutputLen:info.OutputLen], info, nil
+}
+
+func matVecHadamardFieldInPlaceOpts[E any, PE FieldElement[E]](name string, v []E, modulus *big.Int, opts Options) error {
+	// in place only the first len(v) rows fit: PadTruncate transforms a zero-padded
+	// copy and writes those back
+	full := v
+	switch opts.Pad {
+	case PadError:
+	case PadTruncate:
+		full = padToPow2(v, nil)
+	case PadIdentity, PadPoint:
+		return fmt.Errorf("%s: pad policy %d returns more rows than v holds; use PadTruncate", name, opts.Pad)
+	default:
+		return fmt.Errorf("%s: unknown pad policy %d", name, opts.Pad)
+	}
+	n := len(full)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	scale, err := normScale(n, opts.Norm, modulus)
+	if err == nil {
+		err = checkOrdering(opts.Order)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	workers := normWorkers(opts.Workers)
+
+	hadamardFieldStagesPar[E, PE](full, workers)
+	if scale != nil {
+		scaleFieldPar[E, PE](full, scale, workers)
+	}
+	switch opts.Order {
+	case OrderDyadic:
+		BitReversePermute(full)
+	case OrderSequency:
+		BitReversePermute(full)
+		copy(full, GrayPermute(full))
+	}
+	if len(full) != len(v) {
+		copy(v, full)
+	}
+	return nil
+}
+
+// normScale returns the factor norm multiplies H·x by (mod order), or nil for NormNone.
+func normScale(n int, norm Normalization, order *big.Int) (*big.Int, error) {
+	switch norm {
+	case NormNone:
+		return nil, nil
+	case NormInverse:
+		return new(big.Int).ModInverse(big.NewInt(int64(n)), order), nil
+	case NormOrthonormal:
+		sqrt := new(big.Int).ModSqrt(big.NewInt(int64(n)), order)
+		if sqrt == nil {
+			return nil, errors.New("n has no square root mod r")
+		}
+		return sqrt.ModInverse(sqrt, order), nil
+	default:
+		return nil, fmt.Errorf("unknown normalization %d", norm)
+	}
+}
+
+// scaleJacPar sets buf[i] <- s·buf[i] in parallel.
+func scaleJacPar[J, A any, PJ JacPoint[J, A]](buf []J, s *big.Int, workers int) {
+	parallelRange(len(buf), workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).ScalarMultiplication(&buf[i], s)
+		}
+	})
+}
+
+// scaleFieldPar sets v[i] <- s·v[i] in parallel.
+func scaleFieldPar[E any, PE FieldElement[E]](v []E, s *big.Int, workers int) {
+	var f E
+	PE(&f).SetBigInt(s)
+	parallelRange(len(v), workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PE(&v[i]).Mul(&v[i], &f)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package fwht
+
+// Normalization selects the scale applied to the transform output.
+type Normalization int
+
+const (
+	// NormNone returns H·x (the plain FWHT).
+	NormNone Normalization = iota
+	// NormInverse returns n⁻¹·H·x = H⁻¹·x (scale taken mod the group order r).
+	NormInverse
+	// NormOrthonormal returns (1/√n)·H·x mod r; applying it twice is the identity.
+	NormOrthonormal
+)
+
+// Options configures the *Opts transform entry points.
+// The zero value is the plain parallel transform with GOMAXPROCS(0) workers.
+type Options struct {
+	Workers int           // <= 0 => GOMAXPROCS(0)
+	Norm    Normalization // output scale
+	Order   Ordering      // output row order
+	Pad     PadPolicy     // non-power-of-two inputs (in-place field transforms: PadError, PadTruncate)
+	PadWith any           // fill point for PadPoint: a value of the affine type A
+}
+
+// PadPolicy selects how a transform treats a non-power-of-two input; the padded
+// length of a point transform is reported in PadInfo.
+type PadPolicy int
+
+const (
+	// PadError rejects non-power-of-two lengths.
+	PadError PadPolicy = iota
+	// PadIdentity appends the group identity up to the next power of two.
+	PadIdentity
+	// PadPoint appends Options.PadWith up to the next power of two.
+	PadPoint
+	// PadTruncate pads like PadIdentity and returns only the first len(in) rows.
+	PadTruncate
+)
+
+// PadInfo describes the padding a transform applied.
+type PadInfo struct {
+	InputLen  int // len(in)
+	PaddedLen int // transform size n (a power of two, 0 for empty input)
+	OutputLen int // len(out): PaddedLen, or InputLen for PadTruncate
+}
+
+// Ordering selects the row order of the transform output.
+type Ordering int
+
+const (
+	// OrderNatural is the Hadamard (Kronecker) order: row i is (-1)^popcount(i&j).
+	OrderNatural Ordering = iota
+	// OrderSequency is the Walsh order: row k has exactly k sign changes.
+	OrderSequency
+	// OrderDyadic is the Paley order: row k is natural row BitReverse(k).
+	OrderDyadic
+)
@@ -0,0 +1,101 @@
+package fwht
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// BitReverse reverses the low logN bits of x.
+func BitReverse(x, logN int) int {
+	if logN == 0 {
+		return 0
+	}
+	return int(bits.Reverse64(uint64(x)) >> (64 - uint(logN)))
+}
+
+// GrayCode returns the binary-reflected Gray code of x.
+func GrayCode(x int) int {
+	return x ^ (x >> 1)
+}
+
+// GrayDecode inverts GrayCode.
+func GrayDecode(g int) int {
+	x := g
+	for s := 1; s < 64; s <<= 1 {
+		x ^= x >> s
+	}
+	return x
+}
+
+// RowToNatural maps row k of an ordered transform of size 2^logN to its
+// natural (Hadamard-order) row index.
+//   - sequency: BitReverse(GrayCode(k))
+//   - dyadic:   BitReverse(k)
+func RowToNatural(order Ordering, k, logN int) int {
+	switch order {
+	case OrderSequency:
+		return BitReverse(GrayCode(k), logN)
+	case OrderDyadic:
+		return BitReverse(k, logN)
+	default:
+		return k
+	}
+}
+
+// NaturalToRow is the inverse of RowToNatural.
+func NaturalToRow(order Ordering, h, logN int) int {
+	switch order {
+	case OrderSequency:
+		return GrayDecode(BitReverse(h, logN))
+	case OrderDyadic:
+		return BitReverse(h, logN)
+	default:
+		return h
+	}
+}
+
+// BitReversePermute swaps v[i] and v[BitReverse(i)] in place. len(v) must be a power of two.
+func BitReversePermute[T any](v []T) {
+	logN := bits.Len(uint(len(v))) - 1
+	for i := range v {
+		if j := BitReverse(i, logN); i < j {
+			v[i], v[j] = v[j], v[i]
+		}
+	}
+}
+
+// GrayPermute returns w with w[k] = v[GrayCode(k)].
+func GrayPermute[T any](v []T) []T {
+	w := make([]T, len(v))
+	for k := range w {
+		w[k] = v[GrayCode(k)]
+	}
+	return w
+}
+
+// Reorder returns the natural-order transform output rearranged into order,
+// i.e. out[k] = natural[RowToNatural(order, k, log2 n)]. OrderNatural returns natural itself.
+func Reorder[T any](natural []T, order Ordering) []T {
+	switch order {
+	case OrderNatural:
+		return natural
+	case OrderDyadic:
+		out := append([]T(nil), natural...)
+		BitReversePermute(out)
+		return out
+	default:
+		out := append([]T(nil), natural...)
+		BitReversePermute(out)
+		return GrayPermute(out)
+	}
+}
+
+// checkOrdering validates an Ordering value.
+func checkOrdering(order Ordering) error {
+	switch order {
+	case OrderNatural, OrderSequency, OrderDyadic:
+		return nil
+	default:
+		return fmt.Errorf("unknown ordering %d", order)
+	}
+}
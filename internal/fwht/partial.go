@@ -0,0 +1,96 @@
+package fwht
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// StageMask returns the mask selecting the given stages (stage r has butterfly
+// distance 1<<r, i.e. transforms along index bit r).
+func StageMask(stages ...int) uint64 {
+	var mask uint64
+	for _, r := range stages {
+		mask |= 1 << uint(r)
+	}
+	return mask
+}
+
+// PartialHadamardEntry returns entry (i, j) of the subcube transform with stage
+// mask: 0 if i and j differ on a bit outside mask, else (-1)^popcount(i&j&mask).
+// The matrix is ⊗_r (H_2 if bit r of mask is set, else I_2) and is symmetric.
+func PartialHadamardEntry(i, j int, mask uint64) int {
+	if uint64(i^j)&^mask != 0 {
+		return 0
+	}
+	if bits.OnesCount64(uint64(i&j)&mask)&1 == 1 {
+		return -1
+	}
+	return 1
+}
+
+// MatVecHadamardStagesPar is MatVecHadamardPar restricted to the stages in mask:
+// the transform runs along the selected index bits and is the identity along the
+// others (e.g. mask = 1<<k - 1 transforms each block of 2^k consecutive points).
+// mask must not select stages >= log2(len(in)).
+func MatVecHadamardStagesPar(in []bn254.G1Affine, mask uint64, workers int) ([]bn254.G1Affine, error) {
+	return matVecHadamardStagesPar[bn254.G1Affine, bn254.G1Jac]("MatVecHadamardStagesPar", in, mask, workers)
+}
+
+// MatVecHadamardFrStagesParInPlace is MatVecHadamardStagesPar over a scalar vector, in place.
+func MatVecHadamardFrStagesParInPlace(v []fr.Element, mask uint64, workers int) error {
+	return matVecHadamardFieldStagesParInPlace[fr.Element]("MatVecHadamardFrStagesParInPlace", v, mask, workers)
+}
+
+// GenericMatVecHadamardStagesPar is MatVecHadamardStagesPar over any short-Weierstrass group.
+func GenericMatVecHadamardStagesPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, mask uint64, workers int) ([]A, error) {
+	return matVecHadamardStagesPar[A, J, PA, PJ]("GenericMatVecHadamardStagesPar", in, mask, workers)
+}
+
+// GenericMatVecHadamardFieldStagesParInPlace is MatVecHadamardFrStagesParInPlace over any field.
+func GenericMatVecHadamardFieldStagesParInPlace[E any, PE FieldElement[E]](v []E, mask uint64, workers int) error {
+	return matVecHadamardFieldStagesParInPlace[E, PE]("GenericMatVecHadamardFieldStagesParInPlace", v, mask, workers)
+}
+
+func matVecHadamardStagesPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, mask uint64, workers int) ([]A, error) {
+	if err := checkStageMask(len(in), mask); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return matVecHadamardParWith[A, J, PA, PJ](name, in, workers, func(buf []J, workers int) {
+		for step := 1; step < len(buf); step <<= 1 {
+			if mask&uint64(step) != 0 {
+				hadamardStagePar[J, PJ](buf, step, workers)
+			}
+		}
+	})
+}
+
+func matVecHadamardFieldStagesParInPlace[E any, PE FieldElement[E]](name string, v []E, mask uint64, workers int) error {
+	n := len(v)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	if err := checkStageMask(n, mask); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	workers = normWorkers(workers)
+	for step := 1; step < n; step <<= 1 {
+		if mask&uint64(step) != 0 {
+			hadamardFieldStagePar[E, PE](v, step, workers)
+		}
+	}
+	return nil
+}
+
+// checkStageMask rejects masks selecting stages a length-n transform does not have.
+func checkStageMask(n int, mask uint64) error {
+	if n > 0 && mask>>uint(bits.Len(uint(n))-1) != 0 {
+		return fmt.Errorf("stage mask %#x selects stages >= log2(%d)", mask, n)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package fwht
+
+import (
+	"errors"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// FWHTProfile is the phase breakdown of one MatVecHadamardPar run.
+type FWHTProfile struct {
+	TAffineToJac    time.Duration   // Affine -> Jacobian
+	TButterfly      []time.Duration // per stage
+	TButterflyTotal time.Duration
+	TJacToAff       time.Duration // Jacobian -> Affine
+	TTotal          time.Duration
+}
+
+// MatVecHadamardParProfile is MatVecHadamardPar (per-point FromJacobian) with timings.
+func MatVecHadamardParProfile(in []bn254.G1Affine, workers int) ([]bn254.G1Affine, FWHTProfile, error) {
+	return matVecHadamardParProfile(in, workers, false)
+}
+
+// MatVecHadamardParBatchProfile is MatVecHadamardParProfile with the final
+// conversion done by BatchJacToAffG1Par (one field inversion).
+func MatVecHadamardParBatchProfile(in []bn254.G1Affine, workers int) ([]bn254.G1Affine, FWHTProfile, error) {
+	return matVecHadamardParProfile(in, workers, true)
+}
+
+func matVecHadamardParProfile(in []bn254.G1Affine, workers int, batch bool) ([]bn254.G1Affine, FWHTProfile, error) {
+	var prof FWHTProfile
+	n := len(in)
+	if n == 0 {
+		return nil, prof, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, prof, errors.New("MatVecHadamardParProfile: length must be a power of two")
+	}
+	workers = normWorkers(workers)
+	t0 := time.Now()
+
+	buf := make([]bn254.G1Jac, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&in[i])
+		}
+	})
+	prof.TAffineToJac = time.Since(t0)
+
+	for step := 1; step < n; step <<= 1 {
+		ts := time.Now()
+		hadamardStagePar(buf, step, workers)
+		d := time.Since(ts)
+		prof.TButterfly = append(prof.TButterfly, d)
+		prof.TButterflyTotal += d
+	}
+
+	tc := time.Now()
+	var out []bn254.G1Affine
+	if batch {
+		out = BatchJacToAffG1Par(buf, workers)
+	} else {
+		out = make([]bn254.G1Affine, n)
+		parallelRange(n, workers, func(i0, i1 int) {
+			for i := i0; i < i1; i++ {
+				out[i].FromJacobian(&buf[i])
+			}
+		})
+	}
+	prof.TJacToAff = time.Since(tc)
+	prof.TTotal = time.Since(t0)
+	return out, prof, nil
+}
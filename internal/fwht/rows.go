@@ -0,0 +1,251 @@
+package fwht
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// HadamardRowsPar returns out[t] = H[rows[t]]·in (natural row order) without
+// computing the whole transform when only a few rows are needed.
+//
+// Rows sharing their low s bits share the first s folding stages
+// (v'[m] = v[2m] ± v[2m+1], sign from row bit s, as in the fwht1024x1024 circuit).
+// Once every distinct row has its own prefix, each row is finished with a signed
+// multi-add over the folded vector using the popcount parity rule
+// H[i][j] = (-1)^popcount(i&j).
+// If the folding cost reaches the cost of a full transform, the full
+// transform is run and the requested rows are gathered instead.
+func HadamardRowsPar(in []bn254.G1Affine, rows []int, workers int) ([]bn254.G1Affine, error) {
+	return hadamardRowsPar[bn254.G1Affine, bn254.G1Jac]("HadamardRowsPar", in, rows, workers)
+}
+
+// GenericHadamardRowsPar is HadamardRowsPar over any short-Weierstrass group.
+func GenericHadamardRowsPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, rows []int, workers int) ([]A, error) {
+	return hadamardRowsPar[A, J, PA, PJ]("GenericHadamardRowsPar", in, rows, workers)
+}
+
+func hadamardRowsPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, rows []int, workers int) ([]A, error) {
+	n := len(in)
+	if n == 0 || len(rows) == 0 {
+		return nil, nil
+	}
+	if !isPowerOfTwo(n) {
+		return nil, fmt.Errorf("%s: length must be a power of two", name)
+	}
+	for _, r := range rows {
+		if r < 0 || r >= n {
+			return nil, fmt.Errorf("%s: row %d out of range [0,%d)", name, r, n)
+		}
+	}
+	workers = normWorkers(workers)
+
+	out := make([]A, len(rows))
+	if !sparseRowsCheaper(n, rows) {
+		full, err := matVecHadamardPar[A, J, PA, PJ](name, in, workers)
+		if err != nil {
+			return nil, err
+		}
+		for t, r := range rows {
+			out[t] = full[r]
+		}
+		return out, nil
+	}
+
+	buf := make([]J, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			PJ(&buf[i]).FromAffine(&in[i])
+		}
+	})
+
+	rowJac := hadamardRowsJac[J, PJ](buf, rows, workers)
+	for t := range rows {
+		PA(&out[t]).FromJacobian(&rowJac[t])
+	}
+	return out, nil
+}
+
+// hadamardRowsJac evaluates H[rows[t]]·v for Jacobian v (len(v) a power of two)
+// by shared-prefix folding followed by signed multi-adds. v is not modified.
+func hadamardRowsJac[J any, PJ JacAdder[J]](v []J, rows []int, workers int) []J {
+	n := len(v)
+	logN := bits.Len(uint(n)) - 1
+	distinct := countDistinct(rows, ^0)
+
+	// level s: one folded vector (length n>>s) per distinct low-s-bit prefix.
+	level := map[int][]J{0: v}
+	s := 0
+	for ; s < logN && len(level) < distinct; s++ {
+		mask := (1 << (s + 1)) - 1
+		half := n >> (s + 1)
+
+		prefixes := distinctValues(rows, mask)
+		next := make(map[int][]J, len(prefixes))
+		for _, q := range prefixes {
+			next[q] = make([]J, half)
+		}
+		parallelTasks(len(prefixes), workers, func(t int) {
+			q := prefixes[t]
+			src := level[q&(mask>>1)]
+			dst := next[q]
+			neg := q>>s&1 == 1
+			for m := 0; m < half; m++ {
+				dst[m] = src[2*m]
+				if neg {
+					PJ(&dst[m]).SubAssign(&src[2*m+1])
+				} else {
+					PJ(&dst[m]).AddAssign(&src[2*m+1])
+				}
+			}
+		})
+		level = next
+	}
+
+	// 남은 비트는 행마다 popcount 부호 규칙으로 signed multi-add
+	mask := (1 << s) - 1
+	out := make([]J, len(rows))
+	parallelTasks(len(rows), workers, func(t int) {
+		high := rows[t] >> s
+		out[t] = signedSum[J, PJ](level[rows[t]&mask], func(j int) bool {
+			return bits.OnesCount(uint(high&j))&1 == 1
+		})
+	})
+	return out
+}
+
+// signedSum returns Σ ±v[j], subtracting v[j] when neg(j). Positive and negative
+// terms are accumulated separately and combined with a single subtraction.
+func signedSum[J any, PJ JacAdder[J]](v []J, neg func(j int) bool) J {
+	var pos, ngt J
+	var hasPos, hasNeg bool
+	for j := range v {
+		if neg(j) {
+			if hasNeg {
+				PJ(&ngt).AddAssign(&v[j])
+			} else {
+				ngt, hasNeg = v[j], true
+			}
+		} else {
+			if hasPos {
+				PJ(&pos).AddAssign(&v[j])
+			} else {
+				pos, hasPos = v[j], true
+			}
+		}
+	}
+	switch {
+	case !hasNeg:
+		return pos
+	case !hasPos:
+		// 0 - ngt: start from the zero Jacobian (Z = 0, the identity).
+		var zero J
+		PJ(&zero).SubAssign(&ngt)
+		return zero
+	}
+	PJ(&pos).SubAssign(&ngt)
+	return pos
+}
+
+// signedSumBlock is how many non-zero terms SignedSumPar hands to signedSum at once.
+const signedSumBlock = 256
+
+// SignedSumPar returns Σ_i signs[i]·in[i] for signs in {-1, 0, +1}, i.e. one row
+// of a ±1/0 matrix times in. Each worker sums its chunk with the same signedSum
+// rule as the sparse rows (positive and negative terms apart, one subtraction),
+// skipping zero signs, and the partial sums are added.
+func SignedSumPar(in []bn254.G1Affine, signs []int8, workers int) (bn254.G1Affine, error) {
+	return signedSumPar[bn254.G1Affine, bn254.G1Jac]("SignedSumPar", in, signs, workers)
+}
+
+// GenericSignedSumPar is SignedSumPar over any short-Weierstrass group.
+func GenericSignedSumPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](in []A, signs []int8, workers int) (A, error) {
+	return signedSumPar[A, J, PA, PJ]("GenericSignedSumPar", in, signs, workers)
+}
+
+func signedSumPar[A, J any, PA AffPoint[A, J], PJ JacPoint[J, A]](name string, in []A, signs []int8, workers int) (A, error) {
+	var out A
+	n := len(in)
+	if len(signs) != n {
+		return out, fmt.Errorf("%s: %d signs for %d points", name, len(signs), n)
+	}
+	for i, s := range signs {
+		if s < -1 || s > 1 {
+			return out, fmt.Errorf("%s: sign %d at index %d not in {-1,0,1}", name, s, i)
+		}
+	}
+	if n == 0 {
+		return out, nil
+	}
+	workers = normWorkers(workers)
+
+	chunk := max(signedSumBlock, (n+workers-1)/workers)
+	parts := make([]J, (n+chunk-1)/chunk)
+	parallelTasks(len(parts), workers, func(t int) {
+		terms := make([]J, 0, signedSumBlock)
+		neg := make([]bool, 0, signedSumBlock)
+		flush := func() {
+			if len(terms) > 0 {
+				s := signedSum[J, PJ](terms, func(j int) bool { return neg[j] })
+				PJ(&parts[t]).AddAssign(&s)
+				terms, neg = terms[:0], neg[:0]
+			}
+		}
+		for i := t * chunk; i < min((t+1)*chunk, n); i++ {
+			if signs[i] == 0 {
+				continue
+			}
+			var j J
+			PJ(&j).FromAffine(&in[i])
+			terms = append(terms, j)
+			neg = append(neg, signs[i] < 0)
+			if len(terms) == signedSumBlock {
+				flush()
+			}
+		}
+		flush()
+	})
+
+	var acc J
+	for t := range parts {
+		PJ(&acc).AddAssign(&parts[t])
+	}
+	PA(&out).FromJacobian(&acc)
+	return out, nil
+}
+
+// sparseRowsCheaper reports whether shared-prefix folding needs fewer point
+// additions than the full transform ((n/2)·log2(n) butterflies, 2 additions each).
+func sparseRowsCheaper(n int, rows []int) bool {
+	logN := bits.Len(uint(n)) - 1
+	full := n * logN
+	distinct := countDistinct(rows, ^0)
+
+	cost, s := 0, 0
+	for p := 1; s < logN && p < distinct; s++ {
+		p = countDistinct(rows, (1<<(s+1))-1)
+		cost += p * (n >> (s + 1))
+	}
+	// signed multi-add: (n>>s) - 1 additions per distinct row
+	cost += distinct * ((n >> s) - 1)
+	return cost < full
+}
+
+// distinctValues returns the distinct values of rows[i]&mask in first-seen order.
+func distinctValues(rows []int, mask int) []int {
+	seen := make(map[int]struct{}, len(rows))
+	out := make([]int, 0, len(rows))
+	for _, r := range rows {
+		q := r & mask
+		if _, ok := seen[q]; !ok {
+			seen[q] = struct{}{}
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+func countDistinct(rows []int, mask int) int {
+	return len(distinctValues(rows, mask))
+}
@@ -0,0 +1,111 @@
+package fwht
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// FieldElement is satisfied by *E for any gnark-crypto field element E
+// (fr.Element, fp.Element of every curve).
+type FieldElement[E any] interface {
+	*E
+	Add(*E, *E) *E
+	Sub(*E, *E) *E
+	Mul(*E, *E) *E
+	SetBigInt(*big.Int) *E
+}
+
+// MatVecHadamardFrSerialInPlace runs the FWHT on a scalar vector in place, single goroutine.
+// Unlike the group version there is no coordinate conversion: butterflies act on v directly.
+func MatVecHadamardFrSerialInPlace(v []fr.Element) error {
+	return matVecHadamardFieldSerialInPlace[fr.Element]("MatVecHadamardFrSerialInPlace", v)
+}
+
+// MatVecHadamardFrParInPlace runs the FWHT on a scalar vector in place using the same
+// stage/tile scheduler as MatVecHadamardPar. workers <= 0 => GOMAXPROCS(0).
+func MatVecHadamardFrParInPlace(v []fr.Element, workers int) error {
+	return matVecHadamardFieldParInPlace[fr.Element]("MatVecHadamardFrParInPlace", v, workers)
+}
+
+// MatVecHadamardFpSerialInPlace is MatVecHadamardFrSerialInPlace over the BN254 base field.
+func MatVecHadamardFpSerialInPlace(v []fp.Element) error {
+	return matVecHadamardFieldSerialInPlace[fp.Element]("MatVecHadamardFpSerialInPlace", v)
+}
+
+// MatVecHadamardFpParInPlace is MatVecHadamardFrParInPlace over the BN254 base field.
+func MatVecHadamardFpParInPlace(v []fp.Element, workers int) error {
+	return matVecHadamardFieldParInPlace[fp.Element]("MatVecHadamardFpParInPlace", v, workers)
+}
+
+// GenericMatVecHadamardFieldSerialInPlace is MatVecHadamardFrSerialInPlace over any field element type.
+func GenericMatVecHadamardFieldSerialInPlace[E any, PE FieldElement[E]](v []E) error {
+	return matVecHadamardFieldSerialInPlace[E, PE]("GenericMatVecHadamardFieldSerialInPlace", v)
+}
+
+// GenericMatVecHadamardFieldParInPlace is MatVecHadamardFrParInPlace over any field element type.
+func GenericMatVecHadamardFieldParInPlace[E any, PE FieldElement[E]](v []E, workers int) error {
+	return matVecHadamardFieldParInPlace[E, PE]("GenericMatVecHadamardFieldParInPlace", v, workers)
+}
+
+func matVecHadamardFieldSerialInPlace[E any, PE FieldElement[E]](name string, v []E) error {
+	n := len(v)
+	if n == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("%s: length must be a power of two", name)
+	}
+	hadamardFieldStagesSerial[E, PE](v)
+	return nil
+}
+
+func matVecHadamardFieldParInPlace[E any, PE FieldElement[E]](name string, v []E, workers int) error {
+	return matVecHadamardFieldInPlaceOpts[E, PE](name, v, nil, Options{Workers: workers})
+}
+
+// hadamardFieldStagesPar is hadamardStagesPar for field elements.
+func hadamardFieldStagesPar[E any, PE FieldElement[E]](v []E, workers int) {
+	for step := 1; step < len(v); step <<= 1 {
+		hadamardFieldStagePar[E, PE](v, step, workers)
+	}
+}
+
+// hadamardFieldStagePar is hadamardStagePar for field elements.
+func hadamardFieldStagePar[E any, PE FieldElement[E]](v []E, step, workers int) {
+	block := step << 1
+	runStage(len(v), step, workers, func(t stageTask) {
+		for b := t.b0; b < t.b1; b++ {
+			base := b * block
+			for j := t.j0; j < t.j1; j++ {
+				fieldButterfly[E, PE](&v[base+j], &v[base+j+step])
+			}
+		}
+	})
+}
+
+// hadamardFieldStagesSerial is hadamardStagesSerial for field elements.
+func hadamardFieldStagesSerial[E any, PE FieldElement[E]](v []E) {
+	n := len(v)
+	stages := bits.Len(uint(n)) - 1
+	half := n >> 1
+
+	for r := 0; r < stages; r++ {
+		mask := (1 << r) - 1
+		dist := 1 << r
+		for k := 0; k < half; k++ {
+			aIdx := ((k >> r) << (r + 1)) | (k & mask)
+			fieldButterfly[E, PE](&v[aIdx], &v[aIdx+dist])
+		}
+	}
+}
+
+// fieldButterfly sets (a, c) <- (a+c, a-c).
+func fieldButterfly[E any, PE FieldElement[E]](a, c *E) {
+	ta, tc := *a, *c
+	PE(a).Add(&ta, &tc)
+	PE(c).Sub(&ta, &tc)
+}
@@ -0,0 +1,113 @@
+package fwht
+
+import "sync"
+
+// stageTask is one tile of a butterfly stage: blocks [b0,b1) × in-block offsets [j0,j1).
+// 블록 b의 버터플라이는 (b*block+j, b*block+j+step) 쌍이다.
+type stageTask struct{ b0, b1, j0, j1 int }
+
+// stageTasks splits the stage with butterfly distance step (n points) into tiles.
+func stageTasks(n, step, workers int) []stageTask {
+	return tileTasks(n/(step<<1), step, workers)
+}
+
+// defaultTasksPerWorker is the tiles-per-worker target of the goroutine-per-stage
+// scheduler (코어 여유 있게 3배); Engine makes it configurable.
+const defaultTasksPerWorker = 3
+
+// tileTasks splits nb blocks × span in-block offsets into tiles.
+func tileTasks(nb, span, workers int) []stageTask {
+	return tileTasksN(nb, span, workers*defaultTasksPerWorker)
+}
+
+// tileTasksN splits nb blocks × span in-block offsets into about targetTasks tiles.
+// 2D 타일링: 블록 수가 충분하면 블록만 나누고, 작으면 j축까지 나눈다.
+func tileTasksN(nb, span, targetTasks int) []stageTask {
+	if targetTasks < 1 {
+		targetTasks = 1
+	}
+
+	tasks := make([]stageTask, 0, targetTasks)
+	if nb >= targetTasks {
+		// 블록만 잘게 분할
+		chunkB := (nb + targetTasks - 1) / targetTasks
+		for b0 := 0; b0 < nb; b0 += chunkB {
+			b1 := b0 + chunkB
+			if b1 > nb {
+				b1 = nb
+			}
+			tasks = append(tasks, stageTask{b0: b0, b1: b1, j0: 0, j1: span})
+		}
+	} else {
+		// nb가 작으면 j축까지 타일링
+		jTiles := targetTasks / max(1, nb)
+		if jTiles < 1 {
+			jTiles = 1
+		}
+		if jTiles > span {
+			jTiles = span
+		}
+		tile := (span + jTiles - 1) / jTiles
+		for j0 := 0; j0 < span; j0 += tile {
+			j1 := j0 + tile
+			if j1 > span {
+				j1 = span
+			}
+			tasks = append(tasks, stageTask{b0: 0, b1: nb, j0: j0, j1: j1})
+		}
+	}
+	return tasks
+}
+
+// runStage executes fn on every tile of one stage using up to workers goroutines.
+func runStage(n, step, workers int, fn func(t stageTask)) {
+	runTasks(stageTasks(n, step, workers), workers, fn)
+}
+
+// runTasks executes fn on every task using up to workers goroutines.
+// A single task is run on the calling goroutine.
+func runTasks(tasks []stageTask, workers int, fn func(t stageTask)) {
+	if len(tasks) <= 1 {
+		// 아주 작은 경우 직렬 처리
+		for _, t := range tasks {
+			fn(t)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	workCh := make(chan stageTask, len(tasks))
+	for _, t := range tasks {
+		workCh <- t
+	}
+	close(workCh)
+
+	W := min(workers, len(tasks))
+	wg.Add(W)
+	for w := 0; w < W; w++ {
+		go func() {
+			defer wg.Done()
+			for t := range workCh {
+				fn(t)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// runStages applies bf to every butterfly pair (a, a+step) of all log2(len(buf))
+// stages, scheduling each stage with runStage.
+func runStages[T any](buf []T, workers int, bf func(a, c *T)) {
+	n := len(buf)
+	for step := 1; step < n; step <<= 1 {
+		block := step << 1
+		runStage(n, step, workers, func(t stageTask) {
+			for b := t.b0; b < t.b1; b++ {
+				base := b * block
+				for j := t.j0; j < t.j1; j++ {
+					bf(&buf[base+j], &buf[base+j+step])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package fwht
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+)
+
+// PointDelta is the input change G[Index] += Delta.
+type PointDelta struct {
+	Index int
+	Delta bn254.G1Affine
+}
+
+// updateGroup is how many deltas share one signed-sum table (2^updateGroup entries).
+const updateGroup = 8
+
+// UpdateHadamardPar turns out = H·G (natural order, no scaling) into H·G' where
+// G' is G with deltas applied: out[i] += Σ_t (-1)^popcount(i&j_t)·Δ_t.
+// Deltas are taken updateGroup at a time; for each group the 2^k signed sums
+// ±Δ_0 ± ... ± Δ_{k-1} are precomputed, so every output needs one mixed addition
+// per group instead of one per delta (n·⌈k/8⌉ additions plus the tables).
+// Indices may repeat. The result is converted back with one batch inversion.
+func UpdateHadamardPar(out []bn254.G1Affine, deltas []PointDelta, workers int) error {
+	n := len(out)
+	if len(deltas) == 0 {
+		return nil
+	}
+	if !isPowerOfTwo(n) {
+		return errors.New("UpdateHadamardPar: length must be a power of two")
+	}
+	for _, d := range deltas {
+		if d.Index < 0 || d.Index >= n {
+			return fmt.Errorf("UpdateHadamardPar: index %d out of range [0,%d)", d.Index, n)
+		}
+	}
+	workers = normWorkers(workers)
+
+	var tables [][]bn254.G1Affine
+	for g0 := 0; g0 < len(deltas); g0 += updateGroup {
+		tables = append(tables, signedSums(deltas[g0:min(g0+updateGroup, len(deltas))]))
+	}
+
+	buf := make([]bn254.G1Jac, n)
+	parallelRange(n, workers, func(i0, i1 int) {
+		for i := i0; i < i1; i++ {
+			buf[i].FromAffine(&out[i])
+			for g, tab := range tables {
+				// bit t of m is the sign bit of H[i][j_t]
+				m := 0
+				for t, d := range deltas[g*updateGroup : min((g+1)*updateGroup, len(deltas))] {
+					m |= (bits.OnesCount(uint(i&d.Index)) & 1) << t
+				}
+				buf[i].AddMixed(&tab[m])
+			}
+		}
+	})
+	copy(out, BatchJacToAffG1Par(buf, workers))
+	return nil
+}
+
+// signedSums returns T[m] = Σ_t (-1)^(bit t of m)·Δ_t for m in [0, 2^len(grp)).
+// T[m] = T[m without its lowest set bit t] - 2Δ_t.
+func signedSums(grp []PointDelta) []bn254.G1Affine {
+	k := len(grp)
+	t := make([]bn254.G1Jac, 1<<k)
+	neg2 := make([]bn254.G1Jac, k)
+	for i := range grp {
+		var d bn254.G1Jac
+		d.FromAffine(&grp[i].Delta)
+		t[0].AddAssign(&d)
+		neg2[i].Double(&d)
+		neg2[i].Neg(&neg2[i])
+	}
+	for m := 1; m < len(t); m++ {
+		t[m] = t[m&(m-1)]
+		t[m].AddAssign(&neg2[bits.TrailingZeros(uint(m))])
+	}
+	return BatchJacToAffG1Par(t, 1)
+}
@@ -1,14 +1,17 @@
 package fwht
 
 import (
-	"sync"
+	"context"
+	"errors"
+	"fmt"
 	"runtime"
+	"sync"
+	"sync/atomic"
 
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 )
 
-
 // isPowerOfTwo returns true if n is a power of two (>0).
 func isPowerOfTwo(n int) bool {
 	return n > 0 && (n&(n-1)) == 0
@@ -29,15 +32,58 @@ func nextPow2(n int) int {
 // PadPointsToPow2 pads an affine point slice to power-of-two length
 // using the group identity (zero-value Affine == infinity).
 func PadPointsToPow2(in []bn254.G1Affine) []bn254.G1Affine {
+	return padToPow2(in, nil)
+}
+
+// padToPow2 copies in into a slice of the next power-of-two length, filling the
+// tail with *fill (nil => zero value); in is returned as is if no padding is needed.
+func padToPow2[A any](in []A, fill *A) []A {
 	if len(in) == 0 || isPowerOfTwo(len(in)) {
 		return in
 	}
-	out := make([]bn254.G1Affine, nextPow2(len(in)))
+	out := make([]A, nextPow2(len(in)))
 	copy(out, in)
 	// remaining entries are zero-value (∞) by default
+	if fill != nil {
+		for i := len(in); i < len(out); i++ {
+			out[i] = *fill
+		}
+	}
 	return out
 }
 
+// padInput applies opts.Pad to in and describes the result.
+func padInput[A any](in []A, opts Options) ([]A, PadInfo, error) {
+	info := PadInfo{InputLen: len(in)}
+	switch opts.Pad {
+	case PadError:
+		if len(in) != 0 && !isPowerOfTwo(len(in)) {
+			return nil, info, errors.New("length must be a power of two")
+		}
+	case PadIdentity, PadTruncate:
+		in = padToPow2(in, nil)
+	case PadPoint:
+		switch p := opts.PadWith.(type) {
+		case A:
+			in = padToPow2(in, &p)
+		case *A:
+			if p == nil {
+				return nil, info, errors.New("PadPoint: nil PadWith")
+			}
+			in = padToPow2(in, p)
+		default:
+			return nil, info, fmt.Errorf("PadPoint: PadWith is %T, want %T", opts.PadWith, *new(A))
+		}
+	default:
+		return nil, info, fmt.Errorf("unknown pad policy %d", opts.Pad)
+	}
+	info.PaddedLen = len(in)
+	info.OutputLen = len(in)
+	if opts.Pad == PadTruncate {
+		info.OutputLen = info.InputLen
+	}
+	return in, info, nil
+}
 
 // parallelRange runs fn on [0,n) split across up to workers chunks.
 func parallelRange(n, workers int, fn func(i0, i1 int)) {
@@ -66,13 +112,12 @@ func parallelRange(n, workers int, fn func(i0, i1 int)) {
 }
 
 // setInfinity sets affine to the point at infinity.
-// (gnark-crypto convention: (0,1) used for infinity)
+// (gnark-crypto convention: (0,0), same as G1Affine.FromJacobian of Z=0)
 func setInfinity(a *bn254.G1Affine) {
 	a.X.SetZero()
-	a.Y.SetOne()
+	a.Y.SetZero()
 }
 
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -86,12 +131,24 @@ func max(a, b int) int {
 	return b
 }
 
-
+// BatchJacToAffG1Par converts Jacobian points to affine with a single field
+// inversion (Montgomery batch trick); Z = 0 maps to (0,0).
 func BatchJacToAffG1Par(in []bn254.G1Jac, workers int) []bn254.G1Affine {
+	out, _ := BatchJacToAffG1ParCtx(context.Background(), in, workers)
+	return out
+}
+
+// BatchJacToAffG1ParCtx is BatchJacToAffG1Par that stops with ctx.Err() if ctx is
+// cancelled; ctx is checked on entry and every ctxCheckInterval points of the
+// serial product passes.
+func BatchJacToAffG1ParCtx(ctx context.Context, in []bn254.G1Jac, workers int) ([]bn254.G1Affine, error) {
 	n := len(in)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	out := make([]bn254.G1Affine, n)
 	if n == 0 {
-		return out
+		return out, nil
 	}
 	if workers <= 0 {
 		workers = runtime.GOMAXPROCS(0)
@@ -108,22 +165,24 @@ func BatchJacToAffG1Par(in []bn254.G1Jac, workers int) []bn254.G1Affine {
 	nonZero := make([]idxZ, 0, n)
 	for i := 0; i < n; i++ {
 		if in[i].Z.IsZero() {
-			// ∞: gnark-crypto convention (0,1)
-			out[i].X.SetZero()
-			out[i].Y.SetOne()
+			// ∞: gnark-crypto convention (0,0)
+			setInfinity(&out[i])
 		} else {
 			nonZero = append(nonZero, idxZ{idx: i, z: in[i].Z})
 		}
 	}
 	k := len(nonZero)
 	if k == 0 {
-		return out
+		return out, nil
 	}
 
 	// 2) 누적 곱(prefix products) P[j] = ∏_{t=0..j} Z_t
 	acc := make([]fp.Element, k)
 	acc[0] = nonZero[0].z
 	for j := 1; j < k; j++ {
+		if j%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		acc[j].Mul(&acc[j-1], &nonZero[j].z)
 	}
 
@@ -134,6 +193,9 @@ func BatchJacToAffG1Par(in []bn254.G1Jac, workers int) []bn254.G1Affine {
 	// 4) 역전파로 모든 1/Z_j 산출
 	invZ := make([]fp.Element, k)
 	for j := k - 1; j >= 0; j-- {
+		if j%ctxCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if j == 0 {
 			invZ[0] = invAll
 		} else {
@@ -162,5 +224,38 @@ func BatchJacToAffG1Par(in []bn254.G1Jac, workers int) []bn254.G1Affine {
 		}
 	})
 
-	return out
-}
\ No newline at end of file
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// parallelTasks runs fn(i) for every i in [0,n) on up to workers goroutines,
+// handing out indices one at a time (for few, uneven tasks).
+func parallelTasks(n, workers int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
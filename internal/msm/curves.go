@@ -0,0 +1,63 @@
+package msm
+
+import (
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// Per-curve, per-group instantiations of GenericNaiveMSM / GenericMultiExpMSM.
+// BN254 G1 keeps the unsuffixed names (NaiveMSM, MultiExpMSM).
+
+// NaiveMSMG2 is NaiveMSM over BN254 G2.
+func NaiveMSMG2(points []bn254.G2Affine, scalars []fr.Element) (bn254.G2Affine, error) {
+	return GenericNaiveMSM[bn254.G2Affine, bn254.G2Jac](points, scalars)
+}
+
+// MultiExpMSMG2 is MultiExpMSM over BN254 G2.
+func MultiExpMSMG2(points []bn254.G2Affine, scalars []fr.Element) (bn254.G2Affine, error) {
+	return GenericMultiExpMSM[bn254.G2Affine, bn254.G2Jac](points, scalars)
+}
+
+// NaiveMSMBLS12381 is NaiveMSM over BLS12-381 G1.
+func NaiveMSMBLS12381(points []bls12381.G1Affine, scalars []bls12381fr.Element) (bls12381.G1Affine, error) {
+	return GenericNaiveMSM[bls12381.G1Affine, bls12381.G1Jac](points, scalars)
+}
+
+// MultiExpMSMBLS12381 is MultiExpMSM over BLS12-381 G1.
+func MultiExpMSMBLS12381(points []bls12381.G1Affine, scalars []bls12381fr.Element) (bls12381.G1Affine, error) {
+	return GenericMultiExpMSM[bls12381.G1Affine, bls12381.G1Jac](points, scalars)
+}
+
+// NaiveMSMBLS12381G2 is NaiveMSM over BLS12-381 G2.
+func NaiveMSMBLS12381G2(points []bls12381.G2Affine, scalars []bls12381fr.Element) (bls12381.G2Affine, error) {
+	return GenericNaiveMSM[bls12381.G2Affine, bls12381.G2Jac](points, scalars)
+}
+
+// MultiExpMSMBLS12381G2 is MultiExpMSM over BLS12-381 G2.
+func MultiExpMSMBLS12381G2(points []bls12381.G2Affine, scalars []bls12381fr.Element) (bls12381.G2Affine, error) {
+	return GenericMultiExpMSM[bls12381.G2Affine, bls12381.G2Jac](points, scalars)
+}
+
+// NaiveMSMBLS12377 is NaiveMSM over BLS12-377 G1.
+func NaiveMSMBLS12377(points []bls12377.G1Affine, scalars []bls12377fr.Element) (bls12377.G1Affine, error) {
+	return GenericNaiveMSM[bls12377.G1Affine, bls12377.G1Jac](points, scalars)
+}
+
+// MultiExpMSMBLS12377 is MultiExpMSM over BLS12-377 G1.
+func MultiExpMSMBLS12377(points []bls12377.G1Affine, scalars []bls12377fr.Element) (bls12377.G1Affine, error) {
+	return GenericMultiExpMSM[bls12377.G1Affine, bls12377.G1Jac](points, scalars)
+}
+
+// NaiveMSMBLS12377G2 is NaiveMSM over BLS12-377 G2.
+func NaiveMSMBLS12377G2(points []bls12377.G2Affine, scalars []bls12377fr.Element) (bls12377.G2Affine, error) {
+	return GenericNaiveMSM[bls12377.G2Affine, bls12377.G2Jac](points, scalars)
+}
+
+// MultiExpMSMBLS12377G2 is MultiExpMSM over BLS12-377 G2.
+func MultiExpMSMBLS12377G2(points []bls12377.G2Affine, scalars []bls12377fr.Element) (bls12377.G2Affine, error) {
+	return GenericMultiExpMSM[bls12377.G2Affine, bls12377.G2Jac](points, scalars)
+}
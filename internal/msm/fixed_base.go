@@ -0,0 +1,279 @@
+package msm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// fixedBasePointBytes is the size of one table point, in memory and on disk (X||Y).
+const fixedBasePointBytes = 2 * fp.Bytes
+
+// fixedBaseMagic starts a table file, followed by n, c and stride as big-endian uint64.
+const fixedBaseMagic = "FBMSMv1\x00"
+
+// fixedBaseChunk is the number of points a worker shifts / decodes at a time.
+const fixedBaseChunk = 1 << 12
+
+// FixedBaseConfig configures NewFixedBaseMSM.
+type FixedBaseConfig struct {
+	Window       int   // bits per (signed) window c; <= 0 => chosen from n and the budget
+	MemoryBudget int64 // bytes for the tables; <= 0 => one table per window
+	Workers      int   // precomputation goroutines; <= 0 => GOMAXPROCS(0)
+}
+
+// FixedBaseMSM answers MSMs against a fixed point vector G. With signed c-bit
+// digits d_{i,w} of s_i and a stride t,
+//
+//	Σ_i s_i·G_i = Σ_{j<t} 2^(c·j) Σ_g Σ_i d_{i,g·t+j}·(2^(c·t·g)·G_i),
+//
+// so the shifted bases T_g = 2^(c·t·g)·G are precomputed once and one set of
+// buckets serves every window g·t+j at once. t = 1 (a table per window) needs
+// no doublings at all; each doubling of t halves the tables and adds one more
+// bucket reduction and c doublings per MSM.
+type FixedBaseMSM struct {
+	n, c, stride, nbWindows int
+	tables                  []bn254.G1Affine // tables[g*n+i] = 2^(c·stride·g)·G_i
+}
+
+// NewFixedBaseMSM precomputes the tables of points. The smallest stride whose
+// tables fit cfg.MemoryBudget is used; a budget below one copy of points still
+// gets that copy (stride = number of windows, i.e. plain signed Pippenger).
+func NewFixedBaseMSM(points []bn254.G1Affine, cfg FixedBaseConfig) (*FixedBaseMSM, error) {
+	n := len(points)
+	if cfg.Window > maxWindow {
+		return nil, errors.New("NewFixedBaseMSM: window too large")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	f := &FixedBaseMSM{n: n, c: cfg.Window}
+	if f.c <= 0 {
+		// cost ≈ n·windows affine bucket additions + stride·2^(c-1) bucket
+		// reductions of two Jacobian additions (~5 affine ones) each
+		best := int64(-1)
+		for c := 2; c <= 16; c++ {
+			t := fixedBaseStride(n, c, cfg.MemoryBudget)
+			cost := int64(n)*int64(signedWindows(c)) + 5*int64(t)<<(c-1)
+			if best < 0 || cost < best {
+				best, f.c = cost, c
+			}
+		}
+	}
+	f.nbWindows = signedWindows(f.c)
+	f.stride = fixedBaseStride(n, f.c, cfg.MemoryBudget)
+	groups := f.groups()
+
+	f.tables = make([]bn254.G1Affine, groups*n)
+	copy(f.tables, points)
+	if groups > 1 {
+		shift := f.c * f.stride
+		runWindows((n+fixedBaseChunk-1)/fixedBaseChunk, workers, func(t int) {
+			i0, i1 := t*fixedBaseChunk, min((t+1)*fixedBaseChunk, n)
+			jac := make([]bn254.G1Jac, i1-i0)
+			for i := range jac {
+				jac[i].FromAffine(&points[i0+i])
+			}
+			for g := 1; g < groups; g++ {
+				for i := range jac {
+					for k := 0; k < shift; k++ {
+						jac[i].DoubleAssign()
+					}
+				}
+				copy(f.tables[g*n+i0:g*n+i1], bn254.BatchJacobianToAffineG1(jac))
+			}
+		})
+	}
+	return f, nil
+}
+
+// signedWindows is the number of signed c-bit digits of an fr element.
+func signedWindows(c int) int {
+	return (fr.Bits + 1 + c - 1) / c
+}
+
+// fixedBaseStride returns the smallest stride whose tables fit budget (<= 0: 1).
+func fixedBaseStride(n, c int, budget int64) int {
+	nbWindows := signedWindows(c)
+	if budget <= 0 {
+		return 1
+	}
+	for t := 1; t < nbWindows; t++ {
+		if int64((nbWindows+t-1)/t)*int64(n)*fixedBasePointBytes <= budget {
+			return t
+		}
+	}
+	return nbWindows
+}
+
+func (f *FixedBaseMSM) groups() int {
+	return (f.nbWindows + f.stride - 1) / f.stride
+}
+
+// Len returns the number of bases.
+func (f *FixedBaseMSM) Len() int { return f.n }
+
+// Window returns the digit width c.
+func (f *FixedBaseMSM) Window() int { return f.c }
+
+// Stride returns the number of windows sharing one table.
+func (f *FixedBaseMSM) Stride() int { return f.stride }
+
+// TableBytes returns the memory held by the tables.
+func (f *FixedBaseMSM) TableBytes() int64 {
+	return int64(len(f.tables)) * fixedBasePointBytes
+}
+
+// MSM computes sum_i scalars[i] * G_i. Work is split into stride × parts tasks,
+// each owning a range [lo, hi) of the batch-affine buckets, so the buckets are
+// reduced once in total: Σ_{b∈[lo,hi)} (b+1)·B_b = acc + lo·sum, where acc and sum
+// are the running-sum reduction and plain sum of the range. workers <= 0 =>
+// GOMAXPROCS(0).
+func (f *FixedBaseMSM) MSM(scalars []fr.Element, workers int) (bn254.G1Affine, error) {
+	if len(scalars) != f.n {
+		return bn254.G1Affine{}, ErrLenMismatch
+	}
+	if f.n == 0 {
+		return bn254.G1Affine{}, nil
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	digits, _ := recodeScalars(scalars, f.c, true, workers)
+	n, groups, nbBuckets := f.n, f.groups(), 1<<(f.c-1)
+	parts := min(nbBuckets, max(1, (workers+f.stride-1)/f.stride))
+	chunk := (nbBuckets + parts - 1) / parts
+	parts = (nbBuckets + chunk - 1) / chunk
+
+	sums := make([]bn254.G1Jac, f.stride*parts)
+	runWindows(len(sums), workers, func(t int) {
+		j, lo := t/parts, (t%parts)*chunk
+		ab := newAffineBuckets(min(chunk, nbBuckets-lo))
+		for g := 0; g < groups; g++ {
+			w := g*f.stride + j
+			if w >= f.nbWindows {
+				break
+			}
+			ab.addAll(f.tables[g*n:(g+1)*n], digits[w*n:(w+1)*n], lo)
+		}
+		ab.flush()
+		acc, sum := reduceAffineBuckets(ab)
+		if lo > 0 {
+			sum.ScalarMultiplication(&sum, big.NewInt(int64(lo)))
+			acc.AddAssign(&sum)
+		}
+		sums[t] = acc
+	})
+
+	// Horner over the windows inside a stride
+	var res bn254.G1Jac
+	for j := f.stride - 1; j >= 0; j-- {
+		if j < f.stride-1 {
+			for k := 0; k < f.c; k++ {
+				res.DoubleAssign()
+			}
+		}
+		for p := 0; p < parts; p++ {
+			res.AddAssign(&sums[j*parts+p])
+		}
+	}
+
+	var out bn254.G1Affine
+	out.FromJacobian(&res)
+	return out, nil
+}
+
+// WriteFile stores the tables at path: the magic, n, c and stride, then every
+// table point as X||Y (big-endian). The file is written to path+".tmp" and renamed.
+func (f *FixedBaseMSM) WriteFile(path string) error {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriterSize(file, 1<<20)
+	w.WriteString(fixedBaseMagic)
+	var hdr [24]byte
+	binary.BigEndian.PutUint64(hdr[0:], uint64(f.n))
+	binary.BigEndian.PutUint64(hdr[8:], uint64(f.c))
+	binary.BigEndian.PutUint64(hdr[16:], uint64(f.stride))
+	w.Write(hdr[:])
+	var raw [fixedBasePointBytes]byte
+	for i := range f.tables {
+		fp.BigEndian.PutElement((*[fp.Bytes]byte)(raw[:fp.Bytes]), f.tables[i].X)
+		fp.BigEndian.PutElement((*[fp.Bytes]byte)(raw[fp.Bytes:]), f.tables[i].Y)
+		w.Write(raw[:])
+	}
+	err = w.Flush()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadFixedBaseMSM loads tables written by WriteFile. Points are checked to be
+// canonical and on the curve (not in the subgroup).
+func ReadFixedBaseMSM(path string) (*FixedBaseMSM, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	hdrLen := len(fixedBaseMagic) + 24
+	if len(raw) < hdrLen || string(raw[:len(fixedBaseMagic)]) != fixedBaseMagic {
+		return nil, errors.New("ReadFixedBaseMSM: not a fixed-base table file")
+	}
+	hdr := raw[len(fixedBaseMagic):hdrLen]
+	n := binary.BigEndian.Uint64(hdr[0:])
+	c := binary.BigEndian.Uint64(hdr[8:])
+	stride := binary.BigEndian.Uint64(hdr[16:])
+	if c < 1 || c > maxWindow || stride < 1 || stride > uint64(signedWindows(int(c))) {
+		return nil, fmt.Errorf("ReadFixedBaseMSM: bad header (c=%d, stride=%d)", c, stride)
+	}
+	f := &FixedBaseMSM{c: int(c), stride: int(stride), nbWindows: signedWindows(int(c))}
+	if want := uint64(f.groups()) * n * fixedBasePointBytes; n > uint64(len(raw)) || uint64(len(raw)-hdrLen) != want {
+		return nil, fmt.Errorf("ReadFixedBaseMSM: size %d does not match n=%d, c=%d, stride=%d", len(raw), n, c, stride)
+	}
+	f.n = int(n)
+	raw = raw[hdrLen:]
+
+	f.tables = make([]bn254.G1Affine, f.groups()*f.n)
+	bad := make([]error, (len(f.tables)+fixedBaseChunk-1)/fixedBaseChunk)
+	runWindows(len(bad), runtime.GOMAXPROCS(0), func(t int) {
+		for i := t * fixedBaseChunk; i < min((t+1)*fixedBaseChunk, len(f.tables)); i++ {
+			r := raw[i*fixedBasePointBytes:]
+			p := &f.tables[i]
+			var err error
+			if p.X, err = fp.BigEndian.Element((*[fp.Bytes]byte)(r[:fp.Bytes])); err == nil {
+				p.Y, err = fp.BigEndian.Element((*[fp.Bytes]byte)(r[fp.Bytes:fixedBasePointBytes]))
+			}
+			if err == nil && !p.IsOnCurve() {
+				err = errors.New("point not on curve")
+			}
+			if err != nil {
+				bad[t] = fmt.Errorf("ReadFixedBaseMSM: point %d: %w", i, err)
+				return
+			}
+		}
+	})
+	for _, err := range bad {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,71 @@
+package msm
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// JacMSM is satisfied by *J for any gnark-crypto Jacobian point type J with
+// affine counterpart A and scalar field element S (bn254.G1Jac, bls12381.G2Jac, ...).
+type JacMSM[J, A, S any] interface {
+	*J
+	FromAffine(*A) *J
+	AddAssign(*J) *J
+	ScalarMultiplication(*J, *big.Int) *J
+	MultiExp([]A, []S, ecc.MultiExpConfig) (*J, error)
+}
+
+// AffMSM is satisfied by *A for the affine counterpart A of Jacobian type J.
+type AffMSM[A, J any] interface {
+	*A
+	FromJacobian(*J) *A
+}
+
+// ScalarMSM is satisfied by *S for any gnark-crypto scalar field element S.
+type ScalarMSM[S any] interface {
+	*S
+	BigInt(*big.Int) *big.Int
+}
+
+// GenericNaiveMSM is NaiveMSM over any gnark-crypto group, e.g.
+//
+//	GenericNaiveMSM[bls12381.G2Affine, bls12381.G2Jac](points, scalars)
+func GenericNaiveMSM[A, J, S any, PA AffMSM[A, J], PJ JacMSM[J, A, S], PS ScalarMSM[S]](points []A, scalars []S) (A, error) {
+	var out A
+	if len(points) != len(scalars) {
+		return out, ErrLenMismatch
+	}
+	if len(points) == 0 {
+		return out, nil
+	}
+
+	var acc J
+	k := new(big.Int)
+	for i := range points {
+		var term J
+		PJ(&term).FromAffine(&points[i])
+		PJ(&term).ScalarMultiplication(&term, PS(&scalars[i]).BigInt(k))
+		PJ(&acc).AddAssign(&term)
+	}
+	PA(&out).FromJacobian(&acc)
+	return out, nil
+}
+
+// GenericMultiExpMSM is MultiExpMSM over any gnark-crypto group.
+func GenericMultiExpMSM[A, J, S any, PA AffMSM[A, J], PJ JacMSM[J, A, S], PS ScalarMSM[S]](points []A, scalars []S) (A, error) {
+	var out A
+	if len(points) != len(scalars) {
+		return out, ErrLenMismatch
+	}
+	if len(points) == 0 {
+		return out, nil
+	}
+
+	var acc J
+	if _, err := PJ(&acc).MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return out, err
+	}
+	PA(&out).FromJacobian(&acc)
+	return out, nil
+}
@@ -0,0 +1,54 @@
+package msm
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+)
+
+var ErrRowOutOfRange = errors.New("row index out of range")
+
+// HadamardRowsRLC computes Agg = sum_i r[i] * (H[rows[i]] · points) as a single MSM.
+// Since Agg = (sum_i r[i]*H[rows[i]]) · points, the combined scalar vector
+// c = H·e with e[rows[i]] += r[i] (H is symmetric) is built by one scalar FWHT,
+// then fed to MultiExpMSM. len(points) must be a power of two.
+func HadamardRowsRLC(points []bn254.G1Affine, rows []int, r []fr.Element) (bn254.G1Affine, error) {
+	n := len(points)
+	if n == 0 {
+		// no stages to select; the result is the identity
+		return HadamardRowsRLCStages(points, rows, r, 0)
+	}
+	if n&(n-1) != 0 {
+		return bn254.G1Affine{}, errors.New("HadamardRowsRLC: length must be a power of two")
+	}
+	allStages := uint64(n - 1)
+	return HadamardRowsRLCStages(points, rows, r, allStages)
+}
+
+// HadamardRowsRLCStages is HadamardRowsRLC for the subcube transform with stage
+// mask (see fwht.MatVecHadamardStagesPar); that matrix is symmetric too, so
+// c = P·e is one fwht.MatVecHadamardFrStagesParInPlace.
+func HadamardRowsRLCStages(points []bn254.G1Affine, rows []int, r []fr.Element, mask uint64) (bn254.G1Affine, error) {
+	if len(rows) != len(r) {
+		return bn254.G1Affine{}, ErrLenMismatch
+	}
+	n := len(points)
+	if n == 0 || len(rows) == 0 {
+		return bn254.G1Affine{}, nil
+	}
+
+	c := make([]fr.Element, n)
+	for i, row := range rows {
+		if row < 0 || row >= n {
+			return bn254.G1Affine{}, ErrRowOutOfRange
+		}
+		c[row].Add(&c[row], &r[i])
+	}
+	if err := fwht.MatVecHadamardFrStagesParInPlace(c, mask, 0); err != nil {
+		return bn254.G1Affine{}, err
+	}
+	return MultiExpMSM(points, c)
+}
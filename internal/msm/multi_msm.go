@@ -0,0 +1,89 @@
+package msm
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// MultiMSM computes out[v] = sum_i scalars[v][i] * points[i] for k scalar vectors
+// against one base vector. Compared to k PippengerMSM calls the vectors share one
+// recoding pass, one (window, vector) task pool whose workers reuse their bucket
+// arrays, and one batch inversion for the k results.
+func MultiMSM(points []bn254.G1Affine, scalars [][]fr.Element, cfg PippengerConfig) ([]bn254.G1Affine, error) {
+	n, k := len(points), len(scalars)
+	for v := range scalars {
+		if len(scalars[v]) != n {
+			return nil, fmt.Errorf("MultiMSM: vector %d: %w", v, ErrLenMismatch)
+		}
+	}
+	if k == 0 {
+		return nil, nil
+	}
+	if n == 0 {
+		return make([]bn254.G1Affine, k), nil
+	}
+	c := cfg.Window
+	if c <= 0 {
+		c = defaultWindow(n)
+	}
+	if c > maxWindow {
+		return nil, errors.New("MultiMSM: window too large")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	digits, nbWindows := recodeBatch(scalars, c, cfg.Signed, workers)
+	nbBuckets := 1<<c - 1
+	if cfg.Signed {
+		nbBuckets = 1 << (c - 1)
+	}
+
+	// tasks are window-major so the k vectors of a window run back to back
+	windows := make([]bn254.G1Jac, nbWindows*k)
+	var pool sync.Pool
+	runWindows(nbWindows*k, workers, func(t int) {
+		w, v := t/k, t%k
+		d := digits[v][w*n : (w+1)*n]
+		if cfg.BatchAffine {
+			ab, _ := pool.Get().(*affineBuckets)
+			if ab == nil {
+				ab = newAffineBuckets(nbBuckets)
+			}
+			ab.addAll(points, d, 0)
+			ab.flush()
+			windows[t], _ = reduceAffineBuckets(ab)
+			ab.reset()
+			pool.Put(ab)
+		} else {
+			buckets, _ := pool.Get().(*[]bn254.G1Jac)
+			if buckets == nil {
+				b := make([]bn254.G1Jac, nbBuckets)
+				buckets = &b
+			}
+			addJacBuckets(*buckets, points, d)
+			windows[t] = reduceJacBuckets(*buckets)
+			clear(*buckets)
+			pool.Put(buckets)
+		}
+	})
+
+	// Horner per vector: res[v] = Σ_w windows[w*k+v]·2^(c·w)
+	res := make([]bn254.G1Jac, k)
+	runWindows(k, workers, func(v int) {
+		res[v] = windows[(nbWindows-1)*k+v]
+		for w := nbWindows - 2; w >= 0; w-- {
+			for j := 0; j < c; j++ {
+				res[v].DoubleAssign()
+			}
+			res[v].AddAssign(&windows[w*k+v])
+		}
+	})
+	return bn254.BatchJacobianToAffineG1(res), nil
+}
@@ -0,0 +1,359 @@
+package msm
+
+import (
+	"errors"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// maxWindow bounds PippengerConfig.Window (2^maxWindow buckets per window).
+const maxWindow = 20
+
+// maxAffineBatch is the largest number of bucket additions sharing one inversion.
+const maxAffineBatch = 256
+
+// PippengerConfig configures PippengerMSM.
+type PippengerConfig struct {
+	Window      int  // bits per window c; <= 0 => chosen from n
+	Signed      bool // signed digits in (-2^(c-1), 2^(c-1)]: half the buckets, one extra window at most
+	BatchAffine bool // affine buckets, additions batched to share one field inversion
+	Workers     int  // windows processed in parallel; <= 0 => GOMAXPROCS(0)
+}
+
+// PippengerMSM computes sum_i scalars[i] * points[i] with the bucket method:
+// every scalar is cut into c-bit digits, each window adds its points into the
+// bucket of their digit, buckets are reduced with a running sum
+// (Σ_b b·B_b = Σ_b Σ_{b'>=b} B_b'), and the windows are combined by Horner
+// with c doublings per window.
+func PippengerMSM(points []bn254.G1Affine, scalars []fr.Element, cfg PippengerConfig) (bn254.G1Affine, error) {
+	if len(points) != len(scalars) {
+		return bn254.G1Affine{}, ErrLenMismatch
+	}
+	n := len(points)
+	if n == 0 {
+		return bn254.G1Affine{}, nil
+	}
+	c := cfg.Window
+	if c <= 0 {
+		c = defaultWindow(n)
+	}
+	if c > maxWindow {
+		return bn254.G1Affine{}, errors.New("PippengerMSM: window too large")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	digits, nbWindows := recodeScalars(scalars, c, cfg.Signed, workers)
+	nbBuckets := 1<<c - 1
+	if cfg.Signed {
+		nbBuckets = 1 << (c - 1)
+	}
+	res := bucketWindows(points, digits, nbWindows, c, nbBuckets, cfg.BatchAffine, workers)
+
+	var out bn254.G1Affine
+	out.FromJacobian(&res)
+	return out, nil
+}
+
+// bucketWindows returns Σ_w 2^(c·w) Σ_i digits[w*n+i]·points[i]: every window is
+// accumulated into its own buckets (|digit|-1, nbBuckets of them) and reduced,
+// then the windows are combined by Horner with c doublings per window.
+func bucketWindows(points []bn254.G1Affine, digits []int32, nbWindows, c, nbBuckets int, batchAffine bool, workers int) bn254.G1Jac {
+	n := len(points)
+	windows := make([]bn254.G1Jac, nbWindows)
+	runWindows(nbWindows, workers, func(w int) {
+		d := digits[w*n : (w+1)*n]
+		if batchAffine {
+			ab := newAffineBuckets(nbBuckets)
+			ab.addAll(points, d, 0)
+			ab.flush()
+			windows[w], _ = reduceAffineBuckets(ab)
+		} else {
+			buckets := make([]bn254.G1Jac, nbBuckets)
+			addJacBuckets(buckets, points, d)
+			windows[w] = reduceJacBuckets(buckets)
+		}
+	})
+
+	// Horner: res = Σ_w windows[w]·2^(c·w)
+	res := windows[nbWindows-1]
+	for w := nbWindows - 2; w >= 0; w-- {
+		for k := 0; k < c; k++ {
+			res.DoubleAssign()
+		}
+		res.AddAssign(&windows[w])
+	}
+	return res
+}
+
+// defaultWindow picks c ≈ log2(n) - 3, clamped to [2, 16].
+func defaultWindow(n int) int {
+	return max(2, min(16, bits.Len(uint(n))-3))
+}
+
+// recodeScalars returns the window-major digit matrix digits[w*n+i] of scalar i.
+// Unsigned digits are the plain c-bit windows of the regular form; signed digits
+// above 2^(c-1) become d - 2^c with a carry into the next window.
+func recodeScalars(scalars []fr.Element, c int, signed bool, workers int) ([]int32, int) {
+	digits, nbWindows := recodeBatch([][]fr.Element{scalars}, c, signed, workers)
+	return digits[0], nbWindows
+}
+
+// recodeBatch is recodeScalars over several scalar vectors in one parallel pass.
+func recodeBatch(vecs [][]fr.Element, c int, signed bool, workers int) ([][]int32, int) {
+	nbWindows := (fr.Bits + c - 1) / c
+	if signed {
+		nbWindows = signedWindows(c)
+	}
+	digits := make([][]int32, len(vecs))
+	total := 0
+	for v := range vecs {
+		digits[v] = make([]int32, nbWindows*len(vecs[v]))
+		total += len(vecs[v])
+	}
+	if total == 0 {
+		return digits, nbWindows
+	}
+	half := int64(1) << (c - 1)
+	chunk := (total + workers - 1) / workers
+	tasks := 0
+	for v := range vecs {
+		tasks += (len(vecs[v]) + chunk - 1) / chunk
+	}
+	runWindows(tasks, workers, func(t int) {
+		// task t -> (vector, chunk)
+		v := 0
+		for ; t >= (len(vecs[v])+chunk-1)/chunk; v++ {
+			t -= (len(vecs[v]) + chunk - 1) / chunk
+		}
+		n := len(vecs[v])
+		for i := t * chunk; i < min((t+1)*chunk, n); i++ {
+			limbs := vecs[v][i].Bits()
+			var carry int64
+			for w := 0; w < nbWindows; w++ {
+				d := int64(windowBits(&limbs, w*c, c)) + carry
+				carry = 0
+				if signed && d > half {
+					d -= 2 * half
+					carry = 1
+				}
+				digits[v][w*n+i] = int32(d)
+			}
+		}
+	})
+	return digits, nbWindows
+}
+
+// windowBits returns bits [off, off+c) of the little-endian limbs.
+func windowBits(limbs *[fr.Limbs]uint64, off, c int) uint64 {
+	l, s := off/64, uint(off%64)
+	if l >= fr.Limbs {
+		return 0
+	}
+	v := limbs[l] >> s
+	if int(s)+c > 64 && l+1 < fr.Limbs {
+		v |= limbs[l+1] << (64 - s)
+	}
+	return v & (1<<uint(c) - 1)
+}
+
+// addJacBuckets adds ±points[i] into bucket |d_i|-1 in Jacobian coordinates.
+func addJacBuckets(buckets []bn254.G1Jac, points []bn254.G1Affine, digits []int32) {
+	var neg bn254.G1Affine
+	for i, d := range digits {
+		switch {
+		case d > 0:
+			buckets[d-1].AddMixed(&points[i])
+		case d < 0:
+			neg.Neg(&points[i])
+			buckets[-d-1].AddMixed(&neg)
+		}
+	}
+}
+
+// reduceJacBuckets returns Σ_b (b+1)·buckets[b] by a running sum from the top.
+func reduceJacBuckets(buckets []bn254.G1Jac) bn254.G1Jac {
+	var sum, acc bn254.G1Jac
+	for b := len(buckets) - 1; b >= 0; b-- {
+		sum.AddAssign(&buckets[b])
+		acc.AddAssign(&sum)
+	}
+	return acc
+}
+
+// reduceAffineBuckets is reduceJacBuckets for affine buckets ((0,0) = empty)
+// plus their Jacobian overflow; it also returns the plain sum of the buckets.
+func reduceAffineBuckets(ab *affineBuckets) (acc, sum bn254.G1Jac) {
+	for b := len(ab.b) - 1; b >= 0; b-- {
+		sum.AddMixed(&ab.b[b])
+		sum.AddAssign(&ab.over[b])
+		acc.AddAssign(&sum)
+	}
+	return acc, sum
+}
+
+// affineBuckets accumulates additions B_b += P in affine coordinates. Pending
+// additions are collected into a batch (at most one per bucket) whose slope
+// denominators x_P - x_B share one inversion; an addition hitting a bucket
+// already in the batch goes to that bucket's Jacobian overflow instead, so
+// skewed digit distributions degrade to the Jacobian cost rather than stalling.
+type affineBuckets struct {
+	b       []bn254.G1Affine
+	over    []bn254.G1Jac
+	inBatch []bool
+	batch   []affineAdd
+	den     []fp.Element
+	size    int
+}
+
+type affineAdd struct {
+	bucket int
+	p      bn254.G1Affine
+}
+
+func newAffineBuckets(nbBuckets int) *affineBuckets {
+	size := max(1, min(maxAffineBatch, nbBuckets/4))
+	return &affineBuckets{
+		b:       make([]bn254.G1Affine, nbBuckets),
+		over:    make([]bn254.G1Jac, nbBuckets),
+		inBatch: make([]bool, nbBuckets),
+		batch:   make([]affineAdd, 0, size),
+		den:     make([]fp.Element, size),
+		size:    size,
+	}
+}
+
+// reset empties the buckets for reuse (the batch is empty after flush).
+func (ab *affineBuckets) reset() {
+	clear(ab.b)
+	clear(ab.over)
+}
+
+// addAll adds ±points[i] into bucket |d_i|-1-lo, skipping digits whose bucket
+// is outside [lo, lo+len(ab.b)); call flush before reading the buckets.
+func (ab *affineBuckets) addAll(points []bn254.G1Affine, digits []int32, lo int) {
+	hi := lo + len(ab.b)
+	for i, d := range digits {
+		b := int(d)
+		if b < 0 {
+			b = -b
+		}
+		b -= 1 + lo
+		if b < 0 || b >= hi-lo {
+			continue
+		}
+		if d > 0 {
+			ab.add(b, points[i])
+		} else {
+			var neg bn254.G1Affine
+			neg.Neg(&points[i])
+			ab.add(b, neg)
+		}
+	}
+}
+
+func (ab *affineBuckets) add(b int, p bn254.G1Affine) {
+	switch {
+	case p.IsInfinity():
+	case ab.b[b].IsInfinity():
+		ab.b[b] = p
+	case ab.inBatch[b]:
+		ab.over[b].AddMixed(&p)
+	default:
+		ab.inBatch[b] = true
+		ab.batch = append(ab.batch, affineAdd{b, p})
+		if len(ab.batch) == ab.size {
+			ab.flush()
+		}
+	}
+}
+
+// flush applies the batch: λ = (y_P - y_B)/(x_P - x_B), x = λ² - x_B - x_P,
+// y = λ(x_B - x) - y_B. Equal x (doubling or P = -B) falls back to Jacobian.
+func (ab *affineBuckets) flush() {
+	if len(ab.batch) == 0 {
+		return
+	}
+	den := ab.den[:len(ab.batch)]
+	for k, a := range ab.batch {
+		den[k].Sub(&a.p.X, &ab.b[a.bucket].X)
+	}
+	batchInvert(den)
+	for k, a := range ab.batch {
+		B := &ab.b[a.bucket]
+		ab.inBatch[a.bucket] = false
+		if den[k].IsZero() {
+			var j bn254.G1Jac
+			j.FromAffine(B)
+			j.AddMixed(&a.p)
+			B.FromJacobian(&j)
+			continue
+		}
+		var lambda, x, y fp.Element
+		lambda.Sub(&a.p.Y, &B.Y)
+		lambda.Mul(&lambda, &den[k])
+		x.Square(&lambda)
+		x.Sub(&x, &B.X)
+		x.Sub(&x, &a.p.X)
+		y.Sub(&B.X, &x)
+		y.Mul(&y, &lambda)
+		y.Sub(&y, &B.Y)
+		B.X, B.Y = x, y
+	}
+	ab.batch = ab.batch[:0]
+}
+
+// batchInvert replaces every non-zero a[i] by its inverse with one inversion
+// (Montgomery trick); zeros are left as is.
+func batchInvert(a []fp.Element) {
+	prefix := make([]fp.Element, len(a))
+	var acc fp.Element
+	acc.SetOne()
+	for i := range a {
+		prefix[i] = acc
+		if !a[i].IsZero() {
+			acc.Mul(&acc, &a[i])
+		}
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].IsZero() {
+			continue
+		}
+		var inv fp.Element
+		inv.Mul(&acc, &prefix[i])
+		acc.Mul(&acc, &a[i])
+		a[i] = inv
+	}
+}
+
+// runWindows runs fn(i) for i in [0,n) on up to workers goroutines.
+func runWindows(n, workers int, fn func(i int)) {
+	workers = min(workers, n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
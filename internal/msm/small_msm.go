@@ -0,0 +1,263 @@
+package msm
+
+import (
+	"fmt"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+
+	"github.com/Han-16/fwhtist/internal/fwht"
+)
+
+// ScalarClass describes the scalars of an MSM. Binary < Ternary < Small < General
+// is the "fits in" order: a vector of one class also belongs to every larger one.
+type ScalarClass int
+
+const (
+	ClassAuto    ScalarClass = iota // detect from the scalars (SmallMSM only)
+	ClassBinary                     // {0, 1}: a subset sum
+	ClassTernary                    // {-1, 0, 1}: a signed sum
+	ClassSmall                      // |s| < 2^Bits, Bits <= smallMaxBits
+	ClassGeneral                    // anything else: MultiExpMSM
+	ClassSparse                     // drop the zero scalars first, then detect the rest
+)
+
+func (c ScalarClass) String() string {
+	switch c {
+	case ClassAuto:
+		return "auto"
+	case ClassBinary:
+		return "binary"
+	case ClassTernary:
+		return "ternary"
+	case ClassSmall:
+		return "small"
+	case ClassGeneral:
+		return "general"
+	case ClassSparse:
+		return "sparse"
+	}
+	return fmt.Sprintf("ScalarClass(%d)", int(c))
+}
+
+// smallMaxBits is the widest |s| handled as ClassSmall (int64 with room for the carry).
+const smallMaxBits = 62
+
+// sparseRatio: ClassAuto compacts the input when at most n/sparseRatio scalars are non-zero.
+const sparseRatio = 4
+
+// ScalarInfo is what ClassifyScalars found.
+type ScalarInfo struct {
+	Class   ScalarClass // smallest of Binary, Ternary, Small, General that fits
+	Bits    int         // bit width of max |s| (ClassSmall and below)
+	NonZero int         // number of non-zero scalars
+}
+
+// SmallMSMConfig configures SmallMSM.
+type SmallMSMConfig struct {
+	Class   ScalarClass // ClassAuto (zero value) => ClassifyScalars decides
+	Bits    int         // ClassSmall: bound on |s| in bits; <= 0 => detected
+	Workers int         // <= 0 => GOMAXPROCS(0)
+}
+
+// SmallMSM computes sum_i scalars[i] * points[i] with a path chosen by the class
+// of the scalars: binary and ternary vectors are one signed sum of the points
+// (fwht.SignedSumPar, the butterflies' signed multi-add); small ones use signed
+// buckets over only the ⌈(Bits+1)/c⌉ windows their width needs; general ones go
+// to MultiExpMSM. Sparse vectors are compacted to their non-zero entries first.
+// A class given in cfg skips the detection of the class but not the check that the
+// scalars fit it.
+func SmallMSM(points []bn254.G1Affine, scalars []fr.Element, cfg SmallMSMConfig) (bn254.G1Affine, error) {
+	if len(points) != len(scalars) {
+		return bn254.G1Affine{}, ErrLenMismatch
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if cfg.Class == ClassGeneral {
+		return MultiExpMSM(points, scalars)
+	}
+	if cfg.Class < ClassAuto || cfg.Class > ClassSparse {
+		return bn254.G1Affine{}, fmt.Errorf("SmallMSM: unknown class %v", cfg.Class)
+	}
+
+	vals, info := classifyScalars(scalars, workers)
+	class := cfg.Class
+	if class == ClassAuto || class == ClassSparse {
+		class = info.Class
+	} else if info.Class > class || (class == ClassSmall && cfg.Bits > 0 && info.Bits > cfg.Bits) {
+		return bn254.G1Affine{}, fmt.Errorf("SmallMSM: scalars (%v, %d bits) do not fit class %v", info.Class, info.Bits, class)
+	}
+	width := info.Bits
+	if class == ClassSmall && cfg.Bits > 0 {
+		width = min(cfg.Bits, smallMaxBits)
+	}
+
+	if cfg.Class == ClassSparse || (cfg.Class == ClassAuto && info.NonZero*sparseRatio <= len(scalars)) {
+		pts := make([]bn254.G1Affine, 0, info.NonZero)
+		sc := make([]fr.Element, 0, info.NonZero)
+		vs := make([]int64, 0, info.NonZero)
+		for i := range scalars {
+			if !scalars[i].IsZero() {
+				pts = append(pts, points[i])
+				sc = append(sc, scalars[i])
+				if vals != nil {
+					vs = append(vs, vals[i])
+				}
+			}
+		}
+		points, scalars = pts, sc
+		if vals != nil {
+			vals = vs
+		}
+	}
+	if len(points) == 0 {
+		return bn254.G1Affine{}, nil
+	}
+
+	switch class {
+	case ClassBinary, ClassTernary:
+		signs := make([]int8, len(vals))
+		for i, v := range vals {
+			signs[i] = int8(v)
+		}
+		return fwht.SignedSumPar(points, signs, workers)
+	case ClassSmall:
+		res := smallBucketMSM(points, vals, width, workers)
+		var out bn254.G1Affine
+		out.FromJacobian(&res)
+		return out, nil
+	}
+	return MultiExpMSM(points, scalars)
+}
+
+// ClassifyScalars returns the smallest class the scalars fit, their bit width
+// and how many are non-zero. Small values are read as signed: s and r - s both
+// have width bits.Len(s).
+func ClassifyScalars(scalars []fr.Element, workers int) ScalarInfo {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	_, info := classifyScalars(scalars, workers)
+	return info
+}
+
+// classifyScalars is ClassifyScalars plus the signed values (nil for ClassGeneral).
+func classifyScalars(scalars []fr.Element, workers int) ([]int64, ScalarInfo) {
+	n := len(scalars)
+	vals := make([]int64, n)
+	chunk := max(1, (n+workers-1)/workers)
+	type partInfo struct {
+		maxAbs  uint64
+		neg     bool
+		wide    bool
+		nonZero int
+	}
+	parts := make([]partInfo, (n+chunk-1)/chunk)
+	runWindows(len(parts), workers, func(t int) {
+		p := &parts[t]
+		for i := t * chunk; i < min((t+1)*chunk, n); i++ {
+			v, ok := smallValue(&scalars[i])
+			if !ok {
+				p.wide = true
+				p.nonZero++
+				continue
+			}
+			vals[i] = v
+			if v != 0 {
+				p.nonZero++
+			}
+			if v < 0 {
+				p.neg = true
+				v = -v
+			}
+			p.maxAbs = max(p.maxAbs, uint64(v))
+		}
+	})
+
+	var info ScalarInfo
+	var maxAbs uint64
+	var neg, wide bool
+	for _, p := range parts {
+		maxAbs = max(maxAbs, p.maxAbs)
+		neg, wide = neg || p.neg, wide || p.wide
+		info.NonZero += p.nonZero
+	}
+	switch {
+	case wide:
+		info.Class, info.Bits = ClassGeneral, fr.Bits
+		return nil, info
+	case maxAbs <= 1 && !neg:
+		info.Class = ClassBinary
+	case maxAbs <= 1:
+		info.Class = ClassTernary
+	default:
+		info.Class = ClassSmall
+	}
+	info.Bits = bits.Len64(maxAbs)
+	return vals, info
+}
+
+// smallValue returns s as a signed integer if s or -s is below 2^smallMaxBits.
+func smallValue(s *fr.Element) (int64, bool) {
+	b := s.Bits()
+	if b[1]|b[2]|b[3] == 0 && b[0] < 1<<smallMaxBits {
+		return int64(b[0]), true
+	}
+	var neg fr.Element
+	neg.Neg(s)
+	b = neg.Bits()
+	if b[1]|b[2]|b[3] == 0 && b[0] < 1<<smallMaxBits {
+		return -int64(b[0]), true
+	}
+	return 0, false
+}
+
+// smallBucketMSM is signed-digit Pippenger for |vals[i]| < 2^width: only
+// ⌈(width+1)/c⌉ windows, with c <= width+1. When there are fewer windows than
+// workers the points are split into parts with their own buckets.
+func smallBucketMSM(points []bn254.G1Affine, vals []int64, width, workers int) bn254.G1Jac {
+	n := len(points)
+	c := min(defaultWindow(n), width+1)
+	nbWindows := (width + 1 + c - 1) / c
+	parts := max(1, min(workers/nbWindows, n/(1<<c)))
+	chunk := (n + parts - 1) / parts
+	parts = (n + chunk - 1) / chunk
+
+	sums := make([]bn254.G1Jac, parts)
+	runWindows(parts, workers, func(t int) {
+		i0, i1 := t*chunk, min((t+1)*chunk, n)
+		m := i1 - i0
+		digits := make([]int32, nbWindows*m)
+		half := int64(1) << (c - 1)
+		mask := int64(1)<<c - 1
+		for i := 0; i < m; i++ {
+			v := vals[i0+i]
+			sign := int64(1)
+			if v < 0 {
+				v, sign = -v, -1
+			}
+			// signed recoding of |v|, then the sign applied to every digit
+			var carry int64
+			for w := 0; w < nbWindows; w++ {
+				d := (v>>(w*c))&mask + carry
+				carry = 0
+				if d > half {
+					d -= 2 * half
+					carry = 1
+				}
+				digits[w*m+i] = int32(sign * d)
+			}
+		}
+		sums[t] = bucketWindows(points[i0:i1], digits, nbWindows, c, 1<<(c-1), true, max(1, workers/parts))
+	})
+
+	var res bn254.G1Jac
+	for t := range sums {
+		res.AddAssign(&sums[t])
+	}
+	return res
+}